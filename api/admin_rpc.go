@@ -0,0 +1,222 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/nk-nigeria/blackjack-module/api/presenter"
+	"github.com/nk-nigeria/blackjack-module/entity"
+)
+
+// Admin RPCs, every change goes through a signal so it is applied on the match loop
+const (
+	RpcAdminListMatches = "blackjack_admin_list_matches"
+	RpcAdminInspect     = "blackjack_admin_inspect_match"
+	RpcAdminKick        = "blackjack_admin_kick_user"
+	RpcAdminAddBots     = "blackjack_admin_add_bots"
+	RpcAdminRemoveBot   = "blackjack_admin_remove_bot"
+	RpcAdminSetLimits   = "blackjack_admin_set_limits"
+	RpcAdminControl     = "blackjack_admin_control_match"
+	RpcAdminBotFillLog  = "blackjack_admin_bot_fill_log"
+)
+
+// operatorServer signs the signals of server to server calls made with the http key
+const operatorServer = "server"
+
+const adminListLimit = 100
+
+// AdminMatchRequest is the payload of the admin RPCs acting on one match
+type AdminMatchRequest struct {
+	MatchId string `json:"match_id"`
+	Reason  string `json:"reason"`
+	// Player of the kick and bot of the bot removal
+	UserId string `json:"user_id"`
+	// Bots to seat
+	Count int `json:"count"`
+	// Asks for the dealer hole card in an inspect, super admins only
+	HoleCard bool  `json:"hole_card"`
+	MinBet   int64 `json:"min_bet"`
+	MaxBet   int64 `json:"max_bet"`
+	// Command of the control RPC, pause, resume, close or refund
+	Command string `json:"command"`
+}
+
+// AdminMatch is a live match as listed to operators, its label without the password
+type AdminMatch struct {
+	MatchId  string                     `json:"match_id"`
+	Size     int32                      `json:"size"`
+	TickRate int32                      `json:"tick_rate"`
+	Label    map[string]json.RawMessage `json:"label"`
+}
+
+type AdminMatchList struct {
+	Matches []*AdminMatch `json:"matches"`
+}
+
+// AdminBotFillLogRequest filters the recent bot fill decisions, an empty match id lists every table
+type AdminBotFillLogRequest struct {
+	MatchId string `json:"match_id"`
+	Limit   int    `json:"limit"`
+}
+
+type AdminBotFillLog struct {
+	Decisions []entity.BotFillDecision `json:"decisions"`
+}
+
+// RegisterAdminRpcs registers the RPCs operators inspect and steer live tables with
+func RegisterAdminRpcs(initializer runtime.Initializer) error {
+	rpcs := map[string]func(context.Context, runtime.Logger, *sql.DB, runtime.NakamaModule, string) (string, error){
+		RpcAdminListMatches: rpcAdminListMatches,
+		RpcAdminInspect:     adminSignalRpc(entity.SignalInspect),
+		RpcAdminKick:        adminSignalRpc(entity.SignalKick),
+		RpcAdminAddBots:     adminSignalRpc(entity.SignalAddBots),
+		RpcAdminRemoveBot:   adminSignalRpc(entity.SignalRemoveBot),
+		RpcAdminSetLimits:   adminSignalRpc(entity.SignalSetLimits),
+		RpcAdminControl:     adminSignalRpc(""),
+		RpcAdminBotFillLog:  rpcAdminBotFillLog,
+	}
+	for id, fn := range rpcs {
+		if err := initializer.RegisterRpc(id, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// adminOperator returns the operator calling an admin RPC and its role
+func adminOperator(ctx context.Context) (string, entity.AdminRole, error) {
+	userId, _ := ctx.Value(runtime.RUNTIME_CTX_USER_ID).(string)
+	if userId == "" {
+		return operatorServer, entity.AdminRoleSuper, nil
+	}
+	role := entity.AdminRoleOf(userId)
+	if role == entity.AdminRoleNone {
+		return "", role, presenter.ErrAdminOnly
+	}
+	return userId, role, nil
+}
+
+func rpcAdminListMatches(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, payload string) (string, error) {
+	if _, _, err := adminOperator(ctx); err != nil {
+		return "", err
+	}
+	matches, err := nk.MatchList(ctx, adminListLimit, true, "", nil, nil, "")
+	if err != nil {
+		logger.WithField("err", err).Error("admin list matches failed")
+		return "", presenter.ErrInternalError
+	}
+	list := &AdminMatchList{Matches: make([]*AdminMatch, 0, len(matches))}
+	for _, match := range matches {
+		if match.GetHandlerName() != entity.ModuleName {
+			continue
+		}
+		label := make(map[string]json.RawMessage)
+		if match.GetLabel() != nil {
+			if err := json.Unmarshal([]byte(match.GetLabel().GetValue()), &label); err != nil {
+				logger.WithField("match_id", match.GetMatchId()).WithField("err", err).Warn("admin list unreadable label")
+			}
+		}
+		delete(label, "password")
+		list.Matches = append(list.Matches, &AdminMatch{
+			MatchId:  match.GetMatchId(),
+			Size:     match.GetSize(),
+			TickRate: match.GetTickRate(),
+			Label:    label,
+		})
+	}
+	out, err := json.Marshal(list)
+	if err != nil {
+		return "", presenter.ErrMarshal
+	}
+	return string(out), nil
+}
+
+// rpcAdminBotFillLog returns the latest bot fill decisions with their reasons, newest first
+func rpcAdminBotFillLog(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, payload string) (string, error) {
+	if _, _, err := adminOperator(ctx); err != nil {
+		return "", err
+	}
+	req := &AdminBotFillLogRequest{}
+	if payload != "" {
+		if err := json.Unmarshal([]byte(payload), req); err != nil {
+			return "", presenter.ErrUnmarshal
+		}
+	}
+	decisions := entity.BotFillLog.Recent(0)
+	list := &AdminBotFillLog{Decisions: make([]entity.BotFillDecision, 0, len(decisions))}
+	for _, d := range decisions {
+		if req.Limit > 0 && len(list.Decisions) >= req.Limit {
+			break
+		}
+		if req.MatchId == "" || d.MatchId == req.MatchId {
+			list.Decisions = append(list.Decisions, d)
+		}
+	}
+	out, err := json.Marshal(list)
+	if err != nil {
+		return "", presenter.ErrMarshal
+	}
+	return string(out), nil
+}
+
+// adminSignalRpc signs the request as a signal of command and sends it to the match,
+// the reply of the match is returned as is. An empty command is the control RPC.
+func adminSignalRpc(command string) func(context.Context, runtime.Logger, *sql.DB, runtime.NakamaModule, string) (string, error) {
+	return func(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, payload string) (string, error) {
+		operatorId, role, err := adminOperator(ctx)
+		if err != nil {
+			return "", err
+		}
+		req := &AdminMatchRequest{}
+		if err := json.Unmarshal([]byte(payload), req); err != nil || req.MatchId == "" {
+			return "", presenter.ErrUnmarshal
+		}
+		if command == "" {
+			switch req.Command {
+			case entity.SignalPause, entity.SignalResume, entity.SignalClose, entity.SignalRefund:
+				command = req.Command
+			default:
+				return "", presenter.ErrUnmarshal
+			}
+		}
+		if command == entity.SignalInspect && req.HoleCard && role != entity.AdminRoleSuper {
+			return "", presenter.ErrSuperAdminOnly
+		}
+		if !entity.MatchSignalsEnabled() {
+			return "", presenter.ErrSignalsDisabled
+		}
+		nonce := make([]byte, 16)
+		if _, err := rand.Read(nonce); err != nil {
+			return "", presenter.ErrInternalError
+		}
+		sig := &entity.MatchSignal{
+			Command:    command,
+			OperatorId: operatorId,
+			Reason:     req.Reason,
+			UserId:     req.UserId,
+			Count:      req.Count,
+			HoleCard:   req.HoleCard,
+			MinBet:     req.MinBet,
+			MaxBet:     req.MaxBet,
+			IssuedAt:   time.Now().Unix(),
+			Nonce:      hex.EncodeToString(nonce),
+		}
+		sig.Sign(req.MatchId)
+		data, err := json.Marshal(sig)
+		if err != nil {
+			return "", presenter.ErrMarshal
+		}
+		reply, err := nk.MatchSignal(ctx, req.MatchId, string(data))
+		if err != nil {
+			logger.WithField("match_id", req.MatchId).WithField("command", command).WithField("err", err).
+				Warn("admin match signal failed")
+			return "", presenter.ErrMatchNotFound
+		}
+		return reply, nil
+	}
+}
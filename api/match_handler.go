@@ -29,9 +29,13 @@ type MatchHandler struct {
 }
 
 func (m *MatchHandler) MatchSignal(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, dispatcher runtime.MatchDispatcher, tick int64, state interface{}, data string) (interface{}, string) {
-	//panic("implement me")
 	s := state.(*entity.MatchState)
-	return s, ""
+	reply := m.processor.ProcessMatchSignal(ctx, logger, nk, db, dispatcher, s, data)
+	result, err := json.Marshal(reply)
+	if err != nil {
+		logger.WithField("err", err).Error("marshal match signal reply failed")
+	}
+	return s, string(result)
 }
 
 func NewMatchHandler(marshaler *proto.MarshalOptions, unmarshaler *proto.UnmarshalOptions) *MatchHandler {
@@ -55,6 +59,7 @@ func (m *MatchHandler) MatchInit(ctx context.Context, logger runtime.Logger, db
 		return nil, entity.TickRate, ""
 	}
 	matchInfo.MatchId, _ = ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string)
+	matchInfo.MaxSize = int32(entity.SeatCountForLabel(matchInfo))
 	labelJSON, err := protojson.Marshal(matchInfo)
 
 	if err != nil {
@@ -65,6 +70,12 @@ func (m *MatchHandler) MatchInit(ctx context.Context, logger runtime.Logger, db
 	logger.Info("match init label= %s", string(labelJSON))
 
 	matchState := entity.NewMatchState(matchInfo)
+	matchState.SeatInitialBots(logger)
+	botUserIds := make([]string, 0, len(matchState.Bots))
+	for _, bot := range matchState.Bots {
+		botUserIds = append(botUserIds, bot.GetUserId())
+	}
+	m.processor.ProcessBotsSeated(ctx, logger, nk, db, &matchState, botUserIds...)
 	// init jp treasure
 	// jpTreasure, _ := cgbdb.GetJackpot(ctx, logger, db, entity.ModuleName)
 	// if jpTreasure != nil {
@@ -7,7 +7,9 @@ import (
 
 	"github.com/heroiclabs/nakama-common/runtime"
 	"github.com/nk-nigeria/blackjack-module/entity"
+	"github.com/nk-nigeria/blackjack-module/pkg/global"
 	"github.com/nk-nigeria/blackjack-module/pkg/packager"
+	"github.com/nk-nigeria/blackjack-module/usecase/service"
 	"github.com/nk-nigeria/blackjack-module/usecase/state_machine"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -25,8 +27,15 @@ func (m *MatchHandler) MatchJoinAttempt(ctx context.Context, logger runtime.Logg
 			return s, false, "wrong password"
 		}
 	}
+	if s.IsBanned(presence.GetUserId()) {
+		return s, false, "kicked from match"
+	}
 	// Check if it's a user attempting to rejoin after a disconnect.
 	if p, _ := s.Presences.Get(presence.GetUserId()); p != nil {
+		if s.IsKicked(presence.GetUserId()) {
+			// the seat of a kicked player or one who asked to leave is not given back
+			return s, false, "kicked from match"
+		}
 		// 	// User rejoining after a disconnect.
 		logger.Info("user %s rejoin after disconnect", presence.GetUserId())
 		s.RemoveLeavePresence(presence.GetUserId())
@@ -36,11 +45,26 @@ func (m *MatchHandler) MatchJoinAttempt(ctx context.Context, logger runtime.Logg
 
 	}
 
-	// join as new user
+	if s.IsSpectator(presence.GetUserId()) {
+		logger.Info("spectator %s rejoin after disconnect", presence.GetUserId())
+		return s, true, ""
+	}
+
+	// join as new user, an earlier spectator join of the user never completed
+	s.RemovePendingSpectator(presence.GetUserId())
 
-	// Check if match is full.
-	if s.Presences.Size()+s.JoinsInProgress >= entity.MaxPresences {
-		return s, false, "match full"
+	// Watch without a seat when asked to or when the table is full
+	spectate := metadata["spectate"] == "true"
+	if spectate || !s.HasFreeSeat() {
+		if !s.CanSpectate() {
+			return s, false, "match full"
+		}
+		if !spectate && len(s.Bots) > 0 {
+			// let the fill policy free a bot seat for this player
+			s.SetHumanWaiting()
+		}
+		s.AddPendingSpectator(presence.GetUserId())
+		return s, true, ""
 	}
 	// check chip balance in wallet before allow join
 	wallet, err := entity.ReadWalletUser(ctx, nk, logger, presence.GetUserId())
@@ -121,9 +145,19 @@ func (m *MatchHandler) MatchLoop(ctx context.Context, logger runtime.Logger, db
 	))
 	if err == state_machine.ErrStateMachineFinish {
 		logger.Info("match need finish")
-
+		// MatchTerminate is not called when the loop ends the match
+		m.terminate(ctx, logger, db, nk, dispatcher, s)
 		return nil
 	}
+	// a table closed by an operator ends once no round is under way
+	if s.IsClosing() {
+		if pbState := m.machine.GetPbState(); pbState == state_machine.StateMatching || pbState == state_machine.StateIdle {
+			logger.Info("match closed by operator")
+			m.processor.ProcessMatchClose(ctx, logger, nk, db, dispatcher, s)
+			m.terminate(ctx, logger, db, nk, dispatcher, s)
+			return nil
+		}
+	}
 
 	return s
 }
@@ -131,6 +165,19 @@ func (m *MatchHandler) MatchLoop(ctx context.Context, logger runtime.Logger, db
 func (m *MatchHandler) MatchTerminate(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, dispatcher runtime.MatchDispatcher, tick int64, state interface{}, graceSeconds int) interface{} {
 	logger.Info("match terminate, state=%v")
 	s := state.(*entity.MatchState)
-	m.processor.ProcessMatchTerminate(ctx, logger, nk, db, dispatcher, s)
+	m.terminate(ctx, logger, db, nk, dispatcher, s)
 	return state
 }
+
+// terminate frees the bots and the bot integration of a match ending, whether the server
+// stops it or its loop does
+func (m *MatchHandler) terminate(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, dispatcher runtime.MatchDispatcher, s *entity.MatchState) {
+	m.processor.ProcessMatchTerminate(ctx, logger, nk, db, dispatcher, s)
+	m.removeBotIntegration(s)
+}
+
+func (m *MatchHandler) removeBotIntegration(s *entity.MatchState) {
+	if registry, ok := global.GetBotIntegrationRegistry().(*service.BotIntegrationRegistry); ok {
+		registry.Remove(s.GetMatchID())
+	}
+}
@@ -10,4 +10,9 @@ var (
 	ErrUnmarshal      = runtime.NewError("cannot unmarshal type", 13) // INTERNAL
 
 	ErrGameFinish = runtime.NewError("game finish", 100) // INTERNAL
+
+	ErrAdminOnly       = runtime.NewError("admin only", 7)                 // PERMISSION_DENIED
+	ErrSuperAdminOnly  = runtime.NewError("super admin only", 7)           // PERMISSION_DENIED
+	ErrMatchNotFound   = runtime.NewError("match not found", 5)            // NOT_FOUND
+	ErrSignalsDisabled = runtime.NewError("match signals are disabled", 9) // FAILED_PRECONDITION
 )
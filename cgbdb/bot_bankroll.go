@@ -0,0 +1,152 @@
+package cgbdb
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+)
+
+const (
+	BotBankrollTableName = "blackjack_bot_bankroll"
+	BotSessionTableName  = "blackjack_bot_session"
+)
+
+// BotBankroll is the long lived bankroll and personality of a bot account
+type BotBankroll struct {
+	UserId       string
+	Personality  string
+	Balance      int64
+	PeakBalance  int64
+	RoundsPlayed int64
+	Sessions     int64
+	// State is the serialized personality state of the bot logic
+	State      []byte
+	UpdateTime time.Time
+}
+
+// BotSession is one seating of a bot at a table, from sit down to leave
+type BotSession struct {
+	UserId       string
+	MatchId      string
+	Personality  string
+	BalanceStart int64
+	BalanceEnd   int64
+	Rounds       int64
+	ExitReason   string
+	StartTime    time.Time
+	EndTime      time.Time
+}
+
+func InitBotBankrollTables(ctx context.Context, logger runtime.Logger, db *sql.DB) error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS ` + BotBankrollTableName + ` (
+			user_id VARCHAR(128) NOT NULL PRIMARY KEY,
+			personality VARCHAR(32) NOT NULL DEFAULT '',
+			balance BIGINT NOT NULL DEFAULT 0,
+			peak_balance BIGINT NOT NULL DEFAULT 0,
+			rounds_played BIGINT NOT NULL DEFAULT 0,
+			sessions BIGINT NOT NULL DEFAULT 0,
+			state JSONB NOT NULL DEFAULT '{}',
+			update_time TIMESTAMPTZ NOT NULL DEFAULT now()
+		);`,
+		`CREATE TABLE IF NOT EXISTS ` + BotSessionTableName + ` (
+			id BIGSERIAL PRIMARY KEY,
+			user_id VARCHAR(128) NOT NULL,
+			match_id VARCHAR(128) NOT NULL DEFAULT '',
+			personality VARCHAR(32) NOT NULL DEFAULT '',
+			balance_start BIGINT NOT NULL DEFAULT 0,
+			balance_end BIGINT NOT NULL DEFAULT 0,
+			rounds BIGINT NOT NULL DEFAULT 0,
+			exit_reason VARCHAR(32) NOT NULL DEFAULT '',
+			start_time TIMESTAMPTZ NOT NULL DEFAULT now(),
+			end_time TIMESTAMPTZ NOT NULL DEFAULT now()
+		);`,
+		`CREATE INDEX IF NOT EXISTS ` + BotSessionTableName + `_user_id_idx ON ` + BotSessionTableName + ` (user_id, end_time);`,
+	}
+	for _, query := range queries {
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			logger.WithField("err", err).Error("db.ExecContext create bot bankroll table error.")
+			return err
+		}
+	}
+	return nil
+}
+
+// GetBotBankrolls returns the stored bankrolls of the given bots, keyed by user id
+func GetBotBankrolls(ctx context.Context, logger runtime.Logger, db *sql.DB, userIds ...string) (map[string]*BotBankroll, error) {
+	res := make(map[string]*BotBankroll, len(userIds))
+	if len(userIds) == 0 {
+		return res, nil
+	}
+	queryBuilder := strings.Builder{}
+	queryBuilder.WriteString(
+		`SELECT user_id, personality, balance, peak_balance, rounds_played, sessions, state, update_time
+				FROM ` + BotBankrollTableName + `
+				WHERE user_id IN ( `)
+	args := make([]any, 0, len(userIds))
+	for i, uid := range userIds {
+		if i > 0 {
+			queryBuilder.WriteString(",")
+		}
+		queryBuilder.WriteString("$" + strconv.Itoa(i+1))
+		args = append(args, uid)
+	}
+	queryBuilder.WriteString(" );")
+	rows, err := db.QueryContext(ctx, queryBuilder.String(), args...)
+	if err != nil {
+		logger.WithField("err", err).Error("db.QueryContext bot bankroll error.")
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		b := &BotBankroll{}
+		if err := rows.Scan(&b.UserId, &b.Personality, &b.Balance, &b.PeakBalance,
+			&b.RoundsPlayed, &b.Sessions, &b.State, &b.UpdateTime); err != nil {
+			logger.WithField("err", err).Error("rows.Scan bot bankroll error.")
+			return nil, err
+		}
+		res[b.UserId] = b
+	}
+	return res, rows.Err()
+}
+
+// UpsertBotBankroll saves the bankroll, peak balance only ever grows
+func UpsertBotBankroll(ctx context.Context, logger runtime.Logger, db *sql.DB, b *BotBankroll) error {
+	state := string(b.State)
+	if state == "" {
+		state = "{}"
+	}
+	query := `INSERT INTO ` + BotBankrollTableName + ` AS t
+				(user_id, personality, balance, peak_balance, rounds_played, sessions, state, update_time)
+			VALUES ($1, $2, $3, $3, $4, $5, $6, now())
+			ON CONFLICT (user_id) DO UPDATE SET
+				personality = EXCLUDED.personality,
+				balance = EXCLUDED.balance,
+				peak_balance = GREATEST(t.peak_balance, EXCLUDED.balance),
+				rounds_played = EXCLUDED.rounds_played,
+				sessions = EXCLUDED.sessions,
+				state = EXCLUDED.state,
+				update_time = now();`
+	_, err := db.ExecContext(ctx, query, b.UserId, b.Personality, b.Balance, b.RoundsPlayed, b.Sessions, state)
+	if err != nil {
+		logger.WithField("err", err).WithField("user_id", b.UserId).Error("db.ExecContext bot bankroll upsert error.")
+	}
+	return err
+}
+
+// InsertBotSession appends a finished session to the bot's bankroll history
+func InsertBotSession(ctx context.Context, logger runtime.Logger, db *sql.DB, s *BotSession) error {
+	query := `INSERT INTO ` + BotSessionTableName + `
+				(user_id, match_id, personality, balance_start, balance_end, rounds, exit_reason, start_time, end_time)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`
+	_, err := db.ExecContext(ctx, query, s.UserId, s.MatchId, s.Personality,
+		s.BalanceStart, s.BalanceEnd, s.Rounds, s.ExitReason, s.StartTime, s.EndTime)
+	if err != nil {
+		logger.WithField("err", err).WithField("user_id", s.UserId).Error("db.ExecContext bot session insert error.")
+	}
+	return err
+}
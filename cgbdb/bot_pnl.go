@@ -0,0 +1,90 @@
+package cgbdb
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+)
+
+const BotPnlDailyTableName = "blackjack_bot_pnl_daily"
+
+// BotPnlDaily is the bot profit and loss of one day at one table stake.
+// Net is bots' side: positive means bots won house money.
+type BotPnlDaily struct {
+	Day       time.Time
+	MarkUnit  int64
+	Rounds    int64
+	ChipBet   int64
+	ChipWin   int64
+	ChipNet   int64
+	ChipTopUp int64
+	ChipTrim  int64
+}
+
+func InitBotPnlDailyTable(ctx context.Context, logger runtime.Logger, db *sql.DB) error {
+	query := `CREATE TABLE IF NOT EXISTS ` + BotPnlDailyTableName + ` (
+			day DATE NOT NULL,
+			mark_unit BIGINT NOT NULL,
+			rounds BIGINT NOT NULL DEFAULT 0,
+			chip_bet BIGINT NOT NULL DEFAULT 0,
+			chip_win BIGINT NOT NULL DEFAULT 0,
+			chip_net BIGINT NOT NULL DEFAULT 0,
+			chip_top_up BIGINT NOT NULL DEFAULT 0,
+			chip_trim BIGINT NOT NULL DEFAULT 0,
+			update_time TIMESTAMPTZ NOT NULL DEFAULT now(),
+			PRIMARY KEY (day, mark_unit)
+		);`
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		logger.WithField("err", err).Error("db.ExecContext create bot pnl daily table error.")
+		return err
+	}
+	return nil
+}
+
+// AddBotPnlDaily adds p to the totals of its day and stake
+func AddBotPnlDaily(ctx context.Context, logger runtime.Logger, db *sql.DB, p *BotPnlDaily) error {
+	query := `INSERT INTO ` + BotPnlDailyTableName + `
+				(day, mark_unit, rounds, chip_bet, chip_win, chip_net, chip_top_up, chip_trim)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (day, mark_unit) DO UPDATE SET
+				rounds = ` + BotPnlDailyTableName + `.rounds + EXCLUDED.rounds,
+				chip_bet = ` + BotPnlDailyTableName + `.chip_bet + EXCLUDED.chip_bet,
+				chip_win = ` + BotPnlDailyTableName + `.chip_win + EXCLUDED.chip_win,
+				chip_net = ` + BotPnlDailyTableName + `.chip_net + EXCLUDED.chip_net,
+				chip_top_up = ` + BotPnlDailyTableName + `.chip_top_up + EXCLUDED.chip_top_up,
+				chip_trim = ` + BotPnlDailyTableName + `.chip_trim + EXCLUDED.chip_trim,
+				update_time = now();`
+	_, err := db.ExecContext(ctx, query, p.Day.UTC().Format("2006-01-02"), p.MarkUnit, p.Rounds,
+		p.ChipBet, p.ChipWin, p.ChipNet, p.ChipTopUp, p.ChipTrim)
+	if err != nil {
+		logger.WithField("err", err).WithField("mark_unit", p.MarkUnit).Error("db.ExecContext bot pnl daily upsert error.")
+	}
+	return err
+}
+
+// GetBotPnlDaily returns the daily bot results between from and to (inclusive)
+func GetBotPnlDaily(ctx context.Context, logger runtime.Logger, db *sql.DB, from, to time.Time) ([]*BotPnlDaily, error) {
+	query := `SELECT day, mark_unit, rounds, chip_bet, chip_win, chip_net, chip_top_up, chip_trim
+			FROM ` + BotPnlDailyTableName + `
+			WHERE day >= $1 AND day <= $2
+			ORDER BY day, mark_unit;`
+	rows, err := db.QueryContext(ctx, query, from.UTC().Format("2006-01-02"), to.UTC().Format("2006-01-02"))
+	if err != nil {
+		logger.WithField("err", err).Error("db.QueryContext bot pnl daily error.")
+		return nil, err
+	}
+	defer rows.Close()
+	ml := make([]*BotPnlDaily, 0)
+	for rows.Next() {
+		p := &BotPnlDaily{}
+		if err := rows.Scan(&p.Day, &p.MarkUnit, &p.Rounds, &p.ChipBet, &p.ChipWin,
+			&p.ChipNet, &p.ChipTopUp, &p.ChipTrim); err != nil {
+			logger.WithField("err", err).Error("rows.Scan bot pnl daily error.")
+			return nil, err
+		}
+		ml = append(ml, p)
+	}
+	return ml, rows.Err()
+}
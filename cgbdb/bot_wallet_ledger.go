@@ -0,0 +1,52 @@
+package cgbdb
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+)
+
+const BotWalletLedgerTableName = "blackjack_bot_wallet_ledger"
+
+// BotWalletLedger is one house funded adjustment of a bot wallet
+type BotWalletLedger struct {
+	UserId        string
+	MatchId       string
+	MarkUnit      int64
+	Action        string
+	Amount        int64
+	BalanceBefore int64
+	BalanceAfter  int64
+}
+
+func InitBotWalletLedgerTable(ctx context.Context, logger runtime.Logger, db *sql.DB) error {
+	query := `CREATE TABLE IF NOT EXISTS ` + BotWalletLedgerTableName + ` (
+			id BIGSERIAL PRIMARY KEY,
+			user_id VARCHAR(128) NOT NULL,
+			match_id VARCHAR(128) NOT NULL DEFAULT '',
+			mark_unit BIGINT NOT NULL DEFAULT 0,
+			action VARCHAR(32) NOT NULL,
+			amount BIGINT NOT NULL,
+			balance_before BIGINT NOT NULL DEFAULT 0,
+			balance_after BIGINT NOT NULL DEFAULT 0,
+			create_time TIMESTAMPTZ NOT NULL DEFAULT now()
+		);`
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		logger.WithField("err", err).Error("db.ExecContext create bot wallet ledger table error.")
+		return err
+	}
+	return nil
+}
+
+func InsertBotWalletLedger(ctx context.Context, logger runtime.Logger, db *sql.DB, l *BotWalletLedger) error {
+	query := `INSERT INTO ` + BotWalletLedgerTableName + `
+				(user_id, match_id, mark_unit, action, amount, balance_before, balance_after)
+			VALUES ($1, $2, $3, $4, $5, $6, $7);`
+	_, err := db.ExecContext(ctx, query, l.UserId, l.MatchId, l.MarkUnit, l.Action,
+		l.Amount, l.BalanceBefore, l.BalanceAfter)
+	if err != nil {
+		logger.WithField("err", err).WithField("user_id", l.UserId).Error("db.ExecContext bot wallet ledger insert error.")
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+package cgbdb
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+)
+
+const ChatReportTableName = "blackjack_chat_report"
+
+// ChatReport is a player reporting another player of the table
+type ChatReport struct {
+	ReporterId string
+	ReportedId string
+	MatchId    string
+	Reason     string
+	// recent chat lines of the reported user, JSON encoded
+	Messages string
+}
+
+func InitChatReportTable(ctx context.Context, logger runtime.Logger, db *sql.DB) error {
+	query := `CREATE TABLE IF NOT EXISTS ` + ChatReportTableName + ` (
+			id BIGSERIAL PRIMARY KEY,
+			reporter_id VARCHAR(128) NOT NULL,
+			reported_id VARCHAR(128) NOT NULL,
+			match_id VARCHAR(128) NOT NULL DEFAULT '',
+			reason VARCHAR(256) NOT NULL DEFAULT '',
+			messages JSONB NOT NULL DEFAULT '[]',
+			create_time TIMESTAMPTZ NOT NULL DEFAULT now()
+		);`
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		logger.WithField("err", err).Error("db.ExecContext create chat report table error.")
+		return err
+	}
+	return nil
+}
+
+func InsertChatReport(ctx context.Context, logger runtime.Logger, db *sql.DB, r *ChatReport) error {
+	messages := r.Messages
+	if messages == "" {
+		messages = "[]"
+	}
+	query := `INSERT INTO ` + ChatReportTableName + `
+				(reporter_id, reported_id, match_id, reason, messages)
+			VALUES ($1, $2, $3, $4, $5);`
+	_, err := db.ExecContext(ctx, query, r.ReporterId, r.ReportedId, r.MatchId, r.Reason, messages)
+	if err != nil {
+		logger.WithField("err", err).WithField("reported_id", r.ReportedId).Error("db.ExecContext chat report insert error.")
+	}
+	return err
+}
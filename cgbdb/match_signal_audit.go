@@ -0,0 +1,70 @@
+package cgbdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+)
+
+const MatchSignalAuditTableName = "blackjack_match_signal_audit"
+
+// MatchSignalAudit is a command an operator signalled a match with, applied or refused
+type MatchSignalAudit struct {
+	OperatorId string
+	MatchId    string
+	Command    string
+	Reason     string
+	// Player or bot the command targets
+	UserId string
+	// Other arguments of the command
+	Params map[string]any
+	Ok     bool
+	Error  string
+	// Chips given back by a refund
+	Refunded int64
+}
+
+func InitMatchSignalAuditTable(ctx context.Context, logger runtime.Logger, db *sql.DB) error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS ` + MatchSignalAuditTableName + ` (
+			id BIGSERIAL PRIMARY KEY,
+			operator_id VARCHAR(128) NOT NULL DEFAULT '',
+			match_id VARCHAR(128) NOT NULL,
+			command VARCHAR(32) NOT NULL DEFAULT '',
+			reason VARCHAR(256) NOT NULL DEFAULT '',
+			user_id VARCHAR(128) NOT NULL DEFAULT '',
+			params JSONB NOT NULL DEFAULT '{}',
+			ok BOOLEAN NOT NULL DEFAULT false,
+			error VARCHAR(64) NOT NULL DEFAULT '',
+			refunded BIGINT NOT NULL DEFAULT 0,
+			create_time TIMESTAMPTZ NOT NULL DEFAULT now()
+		);`,
+		`CREATE INDEX IF NOT EXISTS ` + MatchSignalAuditTableName + `_match_idx
+			ON ` + MatchSignalAuditTableName + ` (match_id, create_time);`,
+	}
+	for _, query := range queries {
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			logger.WithField("err", err).Error("db.ExecContext create match signal audit table error.")
+			return err
+		}
+	}
+	return nil
+}
+
+func InsertMatchSignalAudit(ctx context.Context, logger runtime.Logger, db *sql.DB, a *MatchSignalAudit) error {
+	params := []byte("{}")
+	if len(a.Params) > 0 {
+		params, _ = json.Marshal(a.Params)
+	}
+	query := `INSERT INTO ` + MatchSignalAuditTableName + `
+				(operator_id, match_id, command, reason, ok, error, refunded, user_id, params)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`
+	_, err := db.ExecContext(ctx, query, a.OperatorId, a.MatchId, a.Command, a.Reason, a.Ok, a.Error, a.Refunded,
+		a.UserId, string(params))
+	if err != nil {
+		logger.WithField("err", err).WithField("match_id", a.MatchId).Error("db.ExecContext match signal audit insert error.")
+	}
+	return err
+}
@@ -0,0 +1,118 @@
+package entity
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+// AdminRole is what a user may do through the admin RPCs
+type AdminRole int
+
+const (
+	AdminRoleNone AdminRole = iota
+	AdminRoleAdmin
+	// AdminRoleSuper may also see the dealer hole card
+	AdminRoleSuper
+)
+
+var (
+	adminMu sync.RWMutex
+	admins  = map[string]AdminRole{}
+)
+
+// SetAdmins replaces the users allowed to call the admin RPCs, super admins win over admins
+func SetAdmins(adminIds, superAdminIds []string) {
+	roles := make(map[string]AdminRole, len(adminIds)+len(superAdminIds))
+	for _, id := range adminIds {
+		if id = strings.TrimSpace(id); id != "" {
+			roles[id] = AdminRoleAdmin
+		}
+	}
+	for _, id := range superAdminIds {
+		if id = strings.TrimSpace(id); id != "" {
+			roles[id] = AdminRoleSuper
+		}
+	}
+	adminMu.Lock()
+	defer adminMu.Unlock()
+	admins = roles
+}
+
+// ParseAdminIds splits a comma separated list of user ids
+func ParseAdminIds(raw string) []string {
+	return strings.Split(raw, ",")
+}
+
+// AdminRoleOf returns the admin role of userId, AdminRoleNone for everyone else
+func AdminRoleOf(userId string) AdminRole {
+	adminMu.RLock()
+	defer adminMu.RUnlock()
+	return admins[userId]
+}
+
+// MatchStateDump is the match state shown to operators, the password is never part of it
+// and the dealer hole card only when a super admin asks
+type MatchStateDump struct {
+	MatchId  string `json:"match_id"`
+	Name     string `json:"name"`
+	MarkUnit int64  `json:"mark_unit"`
+	Open     bool   `json:"open"`
+	// The table as a spectator sees it
+	Table *TableSnapshot `json:"table"`
+
+	Presences  []string `json:"presences"`
+	Playing    []string `json:"playing"`
+	Leaving    []string `json:"leaving"`
+	Bots       []string `json:"bots"`
+	Spectators []string `json:"spectators"`
+	SeatQueue  []string `json:"seat_queue"`
+
+	Status    *TableStatus          `json:"status,omitempty"`
+	Limits    *TableLimits          `json:"limits,omitempty"`
+	TimeBanks map[string]int        `json:"time_banks"`
+	PropBets  map[string][]*PropBet `json:"prop_bets"`
+	// Every dealer card, hole card included
+	DealerHand *pb.BlackjackPlayerHand `json:"dealer_hand,omitempty"`
+}
+
+// Dump builds the match state for operators, holeCard adds the full dealer hand
+func (s *MatchState) Dump(holeCard bool) *MatchStateDump {
+	dump := &MatchStateDump{
+		MatchId:    s.Label.MatchId,
+		Name:       s.Label.Name,
+		MarkUnit:   int64(s.Label.MarkUnit),
+		Open:       s.Label.Open,
+		Table:      s.TableSnapshot(""),
+		Presences:  presenceUserIds(s.GetPresences()),
+		Playing:    presenceUserIds(s.GetPlayingPresences()),
+		Leaving:    presenceUserIds(s.GetLeavePresences()),
+		Bots:       presenceUserIds(s.GetBotPresences()),
+		Spectators: presenceUserIds(s.GetSpectators()),
+		SeatQueue:  append([]string{}, s.seatQueue...),
+		Status:     s.GetTableStatus(),
+		Limits:     s.GetTableLimits(),
+		TimeBanks:  make(map[string]int, len(s.timeBanks)),
+		PropBets:   make(map[string][]*PropBet, len(s.propBets)),
+	}
+	for userId, bank := range s.timeBanks {
+		dump.TimeBanks[userId] = bank.seconds
+	}
+	for userId, bets := range s.propBets {
+		dump.PropBets[userId] = bets
+	}
+	if holeCard && len(s.dealerHand.first) > 0 {
+		dump.DealerHand = s.GetDealerHand()
+	}
+	return dump
+}
+
+func presenceUserIds(presences []runtime.Presence) []string {
+	userIds := make([]string, 0, len(presences))
+	for _, presence := range presences {
+		userIds = append(userIds, presence.GetUserId())
+	}
+	return userIds
+}
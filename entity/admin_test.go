@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"testing"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func TestAdminRoleOf(t *testing.T) {
+	defer SetAdmins(nil, nil)
+	SetAdmins(ParseAdminIds("ops-1, ops-2,"), ParseAdminIds("ops-2,root"))
+	cases := map[string]AdminRole{
+		"ops-1":  AdminRoleAdmin,
+		"ops-2":  AdminRoleSuper,
+		"root":   AdminRoleSuper,
+		"player": AdminRoleNone,
+		"":       AdminRoleNone,
+	}
+	for userId, want := range cases {
+		if got := AdminRoleOf(userId); got != want {
+			t.Errorf("AdminRoleOf(%q) = %v, want %v", userId, got, want)
+		}
+	}
+}
+
+func TestDumpHidesHoleCard(t *testing.T) {
+	state := NewMatchState(&pb.Match{MatchId: "m1", MarkUnit: 10, Password: "secret"})
+	state.SetGameState(pb.GameState_GAME_STATE_PLAY)
+	state.dealerHand.first = []*pb.Card{
+		{Rank: pb.CardRank_RANK_K, Suit: pb.CardSuit_SUIT_HEARTS},
+		{Rank: pb.CardRank_RANK_7, Suit: pb.CardSuit_SUIT_SPADES},
+	}
+
+	dump := state.Dump(false)
+	if dump.DealerHand != nil {
+		t.Errorf("the dealer hand should only be dumped on request")
+	}
+	if cards := dump.Table.DealerHand.First.Cards; len(cards) != 2 || cards[1].Rank != pb.CardRank_RANK_UNSPECIFIED {
+		t.Errorf("the table should show the hole card face down, got %v", cards)
+	}
+	if dump := state.Dump(true); dump.DealerHand == nil || len(dump.DealerHand.First.Cards) != 2 {
+		t.Errorf("the full dealer hand should be dumped for super admins")
+	}
+}
@@ -0,0 +1,214 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Reasons sent to a client kicked off the table
+const (
+	KickReasonAfk            = "afk"
+	KickReasonSitOutExpired  = "sit_out_expired"
+	KickReasonNotEnoughChips = "not_enough_chips"
+	KickReasonLeaveRequest   = "leave_request"
+	KickReasonLeft           = "left_table"
+	KickReasonTableClosed    = "table_closed"
+	KickReasonOperator       = "operator_kick"
+)
+
+// Actions taken on an idle player
+const (
+	AfkActionWarn   = "warn"
+	AfkActionSitOut = "sit_out"
+	AfkActionKick   = "kick"
+)
+
+// AfkPolicy declares what happens to players idle for several betting rounds in a row,
+// a round is idle when the player neither bet nor acted since the previous one
+type AfkPolicy struct {
+	// WarnRounds idle before the player is warned, 0 means no warning
+	WarnRounds int `json:"warn_rounds"`
+	// SitOutRounds idle before the player is sat out, 0 means the player is kicked instead
+	SitOutRounds int `json:"sit_out_rounds"`
+	// KickRounds idle before the player is kicked, 0 means never
+	KickRounds int `json:"kick_rounds"`
+	// QueueKickRounds idle before the player is kicked while spectators queue for a seat,
+	// 0 falls back to the other thresholds
+	QueueKickRounds int `json:"queue_kick_rounds"`
+}
+
+// DefaultAfkPolicy applies to every stake without its own policy
+var DefaultAfkPolicy = AfkPolicy{
+	WarnRounds:      1,
+	SitOutRounds:    2,
+	KickRounds:      4,
+	QueueKickRounds: 2,
+}
+
+// afkPolicies holds the AFK policy of each stake level
+var afkPolicies = newStakeTable(DefaultAfkPolicy)
+
+// ParseAfkPolicies parses a per stake config like {"0":{"warn_rounds":1,"sit_out_rounds":2}}
+func ParseAfkPolicies(raw string) (map[int64]AfkPolicy, error) {
+	policies := make(map[int64]AfkPolicy)
+	if err := json.Unmarshal([]byte(raw), &policies); err != nil {
+		return nil, err
+	}
+	for stake, p := range policies {
+		if stake < 0 || p.WarnRounds < 0 || p.SitOutRounds < 0 || p.KickRounds < 0 || p.QueueKickRounds < 0 {
+			return nil, fmt.Errorf("negative value in afk policy of stake %d", stake)
+		}
+	}
+	if len(policies) == 0 {
+		return nil, fmt.Errorf("no afk policy")
+	}
+	return policies, nil
+}
+
+// SetAfkPolicies replaces the AFK policies, nil restores the default
+func SetAfkPolicies(policies map[int64]AfkPolicy) {
+	afkPolicies.Set(policies)
+}
+
+// AfkPolicyForStake returns the policy of the highest stake level not above markUnit
+func AfkPolicyForStake(markUnit int64) AfkPolicy {
+	return afkPolicies.For(markUnit)
+}
+
+// Action returns what to do with a player idle for idleRounds, empty when nothing
+func (p AfkPolicy) Action(idleRounds int, queueWaiting bool) string {
+	if idleRounds <= 0 {
+		return ""
+	}
+	if queueWaiting && p.QueueKickRounds > 0 && idleRounds >= p.QueueKickRounds {
+		return AfkActionKick
+	}
+	if p.KickRounds > 0 && idleRounds >= p.KickRounds {
+		return AfkActionKick
+	}
+	if p.SitOutRounds > 0 && idleRounds >= p.SitOutRounds {
+		return AfkActionSitOut
+	}
+	if p.WarnRounds > 0 && idleRounds >= p.WarnRounds {
+		return AfkActionWarn
+	}
+	return ""
+}
+
+// roundsBefore returns the idle rounds left before action, -1 when it never happens
+func (p AfkPolicy) roundsBefore(action string, idleRounds int, queueWaiting bool) int {
+	for n := idleRounds + 1; n <= idleRounds+100; n++ {
+		if p.Action(n, queueWaiting) == action {
+			return n - idleRounds
+		}
+	}
+	return -1
+}
+
+// AfkWarning is sent to an idle player before the next action is taken
+type AfkWarning struct {
+	IdleRounds int `json:"idle_rounds"`
+	// Next action and the idle rounds left before it
+	Action     string `json:"action"`
+	RoundsLeft int    `json:"rounds_left"`
+}
+
+type KickReason struct {
+	Reason string `json:"reason"`
+}
+
+// AfkDecision is the action taken on one idle player
+type AfkDecision struct {
+	UserId     string
+	Action     string
+	Reason     string
+	IdleRounds int
+	Warning    *AfkWarning
+}
+
+// CountAfkRound counts a betting round for the seated players, those who
+// did not interact since the previous one are one round more idle.
+// Only rounds that dealt cards count, a betting round nobody bet in is skipped.
+func (s *MatchState) CountAfkRound() {
+	if !s.afkRoundDealt {
+		return
+	}
+	s.afkRoundDealt = false
+	for _, presence := range s.GetPresences() {
+		userId := presence.GetUserId()
+		if s.IsBot(userId) || s.IsSittingOut(userId) || s.IsDisconnected(userId) {
+			delete(s.afkRounds, userId)
+			continue
+		}
+		if s.afkActive[userId] {
+			delete(s.afkRounds, userId)
+			continue
+		}
+		s.afkRounds[userId]++
+	}
+	s.afkActive = make(map[string]bool)
+}
+
+// GetAfkRounds returns the betting rounds userId has been idle in a row
+func (s *MatchState) GetAfkRounds(userId string) int {
+	return s.afkRounds[userId]
+}
+
+// CheckAfk applies the AFK policy of the table, seats of expired sit-outs are given up too
+func (s *MatchState) CheckAfk(now time.Time) []*AfkDecision {
+	policy := AfkPolicyForStake(int64(s.Label.MarkUnit))
+	queueWaiting := len(s.seatQueue) > 0
+	decisions := make([]*AfkDecision, 0)
+	for _, presence := range s.GetPresences() {
+		userId := presence.GetUserId()
+		if s.isSitOutExpired(userId, now) {
+			decisions = append(decisions, &AfkDecision{UserId: userId, Action: AfkActionKick, Reason: KickReasonSitOutExpired})
+			continue
+		}
+		idle := s.afkRounds[userId]
+		d := &AfkDecision{UserId: userId, Action: policy.Action(idle, queueWaiting), Reason: KickReasonAfk, IdleRounds: idle}
+		switch d.Action {
+		case "":
+			continue
+		case AfkActionWarn:
+			d.Warning = &AfkWarning{IdleRounds: idle, Action: AfkActionKick,
+				RoundsLeft: policy.roundsBefore(AfkActionKick, idle, queueWaiting)}
+			if left := policy.roundsBefore(AfkActionSitOut, idle, queueWaiting); left > 0 &&
+				(d.Warning.RoundsLeft < 0 || left < d.Warning.RoundsLeft) {
+				d.Warning.Action, d.Warning.RoundsLeft = AfkActionSitOut, left
+			}
+		}
+		decisions = append(decisions, d)
+	}
+	return decisions
+}
+
+// SetKickReason records why userId is about to be kicked off the table
+func (s *MatchState) SetKickReason(userId, reason string) {
+	s.kickReasons[userId] = reason
+}
+
+// IsKicked reports whether userId was kicked or asked to leave and is on the way out,
+// such a leave is not a disconnect and the player may not take the seat back
+func (s *MatchState) IsKicked(userId string) bool {
+	_, found := s.kickReasons[userId]
+	return found
+}
+
+// PopKickReason returns and forgets why userId was kicked, KickReasonLeft by default
+func (s *MatchState) PopKickReason(userId string) string {
+	reason, found := s.kickReasons[userId]
+	if !found {
+		return KickReasonLeft
+	}
+	delete(s.kickReasons, userId)
+	return reason
+}
+
+// override, any interaction makes the player active again
+func (s *MatchState) ResetUserNotInteract(userId string) {
+	s.baseMatchState.ResetUserNotInteract(userId)
+	s.afkActive[userId] = true
+	delete(s.afkRounds, userId)
+}
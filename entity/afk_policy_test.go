@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAfkPolicyAction(t *testing.T) {
+	p := AfkPolicy{WarnRounds: 1, SitOutRounds: 2, KickRounds: 4, QueueKickRounds: 2}
+	cases := []struct {
+		idle  int
+		queue bool
+		want  string
+	}{
+		{0, false, ""},
+		{1, false, AfkActionWarn},
+		{2, false, AfkActionSitOut},
+		{2, true, AfkActionKick},
+		{4, false, AfkActionKick},
+	}
+	for _, c := range cases {
+		if got := p.Action(c.idle, c.queue); got != c.want {
+			t.Errorf("idle %d queue %v: expected %q, got %q", c.idle, c.queue, c.want, got)
+		}
+	}
+	if got := (AfkPolicy{KickRounds: 2}).Action(1, false); got != "" {
+		t.Errorf("Expected no warning without WarnRounds, got %q", got)
+	}
+}
+
+func TestParseAfkPolicies(t *testing.T) {
+	policies, err := ParseAfkPolicies(`{"0":{"warn_rounds":1,"kick_rounds":2},"10000":{"sit_out_rounds":1}}`)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	SetAfkPolicies(policies)
+	defer SetAfkPolicies(nil)
+	if p := AfkPolicyForStake(500); p.KickRounds != 2 {
+		t.Errorf("Expected the base policy, got %+v", p)
+	}
+	if p := AfkPolicyForStake(20000); p.SitOutRounds != 1 {
+		t.Errorf("Expected the high stake policy, got %+v", p)
+	}
+	if _, err := ParseAfkPolicies(`{"0":{"warn_rounds":-1}}`); err == nil {
+		t.Errorf("Expected negative rounds to be refused")
+	}
+}
+
+func TestCheckAfk(t *testing.T) {
+	SetAfkPolicies(map[int64]AfkPolicy{0: {WarnRounds: 1, SitOutRounds: 2, KickRounds: 4, QueueKickRounds: 2}})
+	defer SetAfkPolicies(nil)
+
+	state := newTestTable("p1", "p2")
+	state.CountAfkRound()
+	if state.GetAfkRounds("p1") != 0 {
+		t.Errorf("Expected a round without cards dealt not to count")
+	}
+	state.ResetUserNotInteract("p2")
+	state.SetupMatchPresence()
+	state.CountAfkRound()
+
+	d := state.CheckAfk(time.Now())
+	if len(d) != 1 || d[0].UserId != "p1" || d[0].Action != AfkActionWarn {
+		t.Fatalf("Expected a warning for p1 only, got %+v", d)
+	}
+	if w := d[0].Warning; w.Action != AfkActionSitOut || w.RoundsLeft != 1 {
+		t.Errorf("Expected sit-out in one round, got %+v", w)
+	}
+
+	state.ResetUserNotInteract("p2")
+	state.SetupMatchPresence()
+	state.CountAfkRound()
+	state.CountAfkRound()
+	if d := state.CheckAfk(time.Now()); len(d) != 1 || d[0].Action != AfkActionSitOut {
+		t.Errorf("Expected p1 to be sat out, got %+v", d)
+	}
+
+	state.AddSpectator(&FakePrecense{UserId: "s1"})
+	state.JoinSeatQueue("s1")
+	if d := state.CheckAfk(time.Now()); len(d) != 1 || d[0].Action != AfkActionKick || d[0].Reason != KickReasonAfk {
+		t.Errorf("Expected p1 to be kicked while a spectator waits, got %+v", d)
+	}
+
+	state.SetKickReason("p1", KickReasonAfk)
+	if !state.IsKicked("p1") {
+		t.Errorf("p1 should be on the way out")
+	}
+	if state.PopKickReason("p1") != KickReasonAfk || state.PopKickReason("p1") != KickReasonLeft || state.IsKicked("p1") {
+		t.Errorf("Expected the kick reason to be sent once")
+	}
+}
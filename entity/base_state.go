@@ -202,7 +202,10 @@ func (s *MatchState) AddBotToMatch(numBot int) []runtime.Presence {
 		s.Bots = append(s.Bots, bots...)
 		for _, bot := range bots {
 			s.Presences.Put(bot.GetUserId(), bot) // bot is Presence
+			s.assignSeat(bot.GetUserId())
 			s.Label.NumBot += 1
+			s.assignBotLogic(bot)
+			BotPool.Seat(bot.GetUserId(), s.Label.MatchId, int64(s.Label.MarkUnit))
 			result = append(result, bot) // append to return list
 			fmt.Printf("[DEBUG] Added bot %s to match\n", bot.GetUserId())
 		}
@@ -228,7 +231,11 @@ func (s *MatchState) RemoveBotFromMatch(botUserID string) (error, runtime.Presen
 	for i, bot := range s.Bots {
 		if bot.GetUserId() == botUserID {
 			s.Bots = append(s.Bots[:i], s.Bots[i+1:]...)
+			delete(s.botLogics, botUserID)
+			delete(s.botSessions, botUserID)
+			s.botScheduler.Cancel(botUserID)
 			BotLoader.FreeBot(botUserID)
+			BotPool.Release(botUserID)
 			break
 		}
 	}
@@ -0,0 +1,163 @@
+package entity
+
+import (
+	"time"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+const (
+	BotExitBusted   = "busted"
+	BotExitUpEnough = "up_enough"
+	BotExitLeave    = "leave"
+	BotExitMatchEnd = "match_end"
+
+	// BotBustBetUnits is how many minimum bets a bot must cover to keep playing
+	BotBustBetUnits = 5
+)
+
+// BotLogicState is the part of a bot's logic kept between sessions
+type BotLogicState struct {
+	Personality   BotPersonality `json:"personality"`
+	RiskTolerance int            `json:"risk_tolerance"`
+	LastResult    int64          `json:"last_result"`
+	LastBet       int64          `json:"last_bet"`
+}
+
+// ExportState returns the state to persist for the next session
+func (b *BlackjackBotLogic) ExportState() BotLogicState {
+	state := BotLogicState{
+		Personality:   b.personality,
+		RiskTolerance: b.riskTolerance,
+		LastResult:    b.lastResult,
+	}
+	if len(b.betHistory) > 0 {
+		state.LastBet = b.betHistory[len(b.betHistory)-1].First
+	}
+	return state
+}
+
+// RestoreBotLogic recreates a bot's logic from a previous session
+func RestoreBotLogic(state BotLogicState) *BlackjackBotLogic {
+	b := NewBotLogicWithPersonality(state.Personality)
+	if state.RiskTolerance > 0 {
+		b.riskTolerance = state.RiskTolerance
+	}
+	b.lastResult = state.LastResult
+	if state.LastBet > 0 {
+		// keeps the martingale progression going
+		b.AddBetHistory(&pb.BlackjackPlayerBet{First: state.LastBet})
+	}
+	return b
+}
+
+// BotExitPolicy makes a bot leave after losing or winning enough in a session,
+// both as a fraction of the balance it sat down with
+type BotExitPolicy struct {
+	StopLoss float64
+	StopWin  float64
+}
+
+var DefaultBotExitPolicies = map[BotPersonality]BotExitPolicy{
+	BotPersonalityCautious:    {StopLoss: 0.3, StopWin: 0.3},
+	BotPersonalityAggressive:  {StopLoss: 0.8, StopWin: 1.0},
+	BotPersonalityMartingale:  {StopLoss: 0.6, StopWin: 0.5},
+	BotPersonalityCardCounter: {StopLoss: 0.5, StopWin: 0.8},
+	BotPersonalityNovice:      {StopLoss: 0.5, StopWin: 0.5},
+}
+
+var defaultBotExitPolicy = BotExitPolicy{StopLoss: 0.5, StopWin: 0.5}
+
+// ExitReason returns why the bot should leave, empty if it keeps playing
+func (p BotExitPolicy) ExitReason(startBalance, balance, minBet int64) string {
+	if balance < minBet*BotBustBetUnits {
+		return BotExitBusted
+	}
+	if startBalance <= 0 {
+		return ""
+	}
+	if float64(balance) <= float64(startBalance)*(1-p.StopLoss) {
+		return BotExitBusted
+	}
+	if float64(balance) >= float64(startBalance)*(1+p.StopWin) {
+		return BotExitUpEnough
+	}
+	return ""
+}
+
+// BotSession tracks a bot from the moment it sits down until it leaves
+type BotSession struct {
+	StartBalance int64
+	Rounds       int64
+	StartTime    time.Time
+	// Totals of previous sessions loaded from the bankroll
+	PriorRounds   int64
+	PriorSessions int64
+	// ExitReason is set once the bot decided to leave
+	ExitReason string
+	// WalletBalance is set when the start balance was read from the bot's wallet
+	WalletBalance bool
+}
+
+func (s *MatchState) startBotSession(userId string, balance int64) {
+	s.botSessions[userId] = &BotSession{
+		StartBalance: balance,
+		StartTime:    time.Now(),
+	}
+}
+
+// RestoreBotBankroll swaps a seated bot's fresh logic for the one it had in previous sessions.
+// The balance stays the one read from the bot's wallet, the stored balance is resumed from
+// when the bot's profile carried none.
+func (s *MatchState) RestoreBotBankroll(userId string, state *BotLogicState, balance, priorRounds, priorSessions int64) {
+	current := s.botLogics[userId]
+	if current == nil {
+		return
+	}
+	if session := s.botSessions[userId]; session != nil && !session.WalletBalance && balance > 0 {
+		current.SetBalance(balance)
+		session.StartBalance = balance
+	}
+	if state != nil && state.Personality.IsValid() {
+		logic := RestoreBotLogic(*state)
+		logic.SetBalance(current.GetBalance())
+		s.botLogics[userId] = logic
+	}
+	if session := s.botSessions[userId]; session != nil {
+		session.PriorRounds = priorRounds
+		session.PriorSessions = priorSessions
+	}
+}
+
+// GetBotSession returns the current session of a seated bot
+func (s *MatchState) GetBotSession(userId string) *BotSession {
+	return s.botSessions[userId]
+}
+
+// EndBotSession removes and returns the session of a bot that leaves the table
+func (s *MatchState) EndBotSession(userId string) *BotSession {
+	session := s.botSessions[userId]
+	delete(s.botSessions, userId)
+	return session
+}
+
+// BotsToRetire marks and returns the bots whose bankroll tells them to leave
+func (s *MatchState) BotsToRetire() map[string]string {
+	res := make(map[string]string)
+	minBet := int64(s.Label.MarkUnit)
+	for userId, logic := range s.botLogics {
+		session := s.botSessions[userId]
+		if session == nil {
+			continue
+		}
+		policy, ok := DefaultBotExitPolicies[logic.GetPersonality()]
+		if !ok {
+			policy = defaultBotExitPolicy
+		}
+		if reason := policy.ExitReason(session.StartBalance, logic.GetBalance(), minBet); reason != "" {
+			session.ExitReason = reason
+			res[userId] = reason
+		}
+	}
+	return res
+}
@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"testing"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func TestBotExitPolicy(t *testing.T) {
+	policy := BotExitPolicy{StopLoss: 0.5, StopWin: 0.5}
+	testCases := []struct {
+		name     string
+		balance  int64
+		expected string
+	}{
+		{"keeps playing", 10000, ""},
+		{"lost half the session", 5000, BotExitBusted},
+		{"cannot cover min bets", 400, BotExitBusted},
+		{"won half the session", 15000, BotExitUpEnough},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := policy.ExitReason(10000, tc.balance, 100); got != tc.expected {
+				t.Errorf("Expected exit reason %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestRestoreBotLogic(t *testing.T) {
+	martingale := NewBotLogicWithPersonality(BotPersonalityMartingale)
+	martingale.SetBalance(100000)
+	bet := martingale.GenerateBotBet().First
+	martingale.RecordResult(-bet)
+
+	restored := RestoreBotLogic(martingale.ExportState())
+	restored.SetBalance(100000)
+	if restored.GetPersonality() != BotPersonalityMartingale {
+		t.Errorf("Expected personality to be restored, got %s", restored.GetPersonality())
+	}
+	if restored.GetRiskTolerance() != martingale.GetRiskTolerance() {
+		t.Errorf("Expected risk tolerance to be restored")
+	}
+	if next := restored.DecideBetAmount(); next != bet*2 {
+		t.Errorf("Expected martingale progression to continue with %d, got %d", bet*2, next)
+	}
+}
+
+func TestBotsToRetire(t *testing.T) {
+	state := NewMatchState(&pb.Match{MarkUnit: 100})
+	for _, userId := range []string{"winner", "loser", "steady"} {
+		state.botLogics[userId] = NewBotLogicWithPersonality(BotPersonalityNovice)
+		state.startBotSession(userId, 10000)
+	}
+	state.RecordBotResults(&pb.BalanceResult{Updates: []*pb.BalanceUpdate{
+		{UserId: "winner", AmountChipCurrent: 16000, TotalChipInMatch: 6000},
+		{UserId: "loser", AmountChipCurrent: 4000, TotalChipInMatch: -6000},
+		{UserId: "steady", AmountChipCurrent: 10500, TotalChipInMatch: 500},
+	}})
+
+	retire := state.BotsToRetire()
+	if retire["winner"] != BotExitUpEnough || retire["loser"] != BotExitBusted {
+		t.Errorf("Unexpected retirements %v", retire)
+	}
+	if _, ok := retire["steady"]; ok {
+		t.Errorf("Expected steady bot to keep playing")
+	}
+	if state.GetBotSession("steady").Rounds != 1 {
+		t.Errorf("Expected session to count the round")
+	}
+	if session := state.EndBotSession("loser"); session == nil || session.ExitReason != BotExitBusted {
+		t.Errorf("Expected ended session to keep its exit reason")
+	}
+}
+
+func TestRestoreBotBankrollBalance(t *testing.T) {
+	state := NewMatchState(&pb.Match{MarkUnit: 100})
+	state.botLogics["fresh"] = NewBotLogicWithPersonality(BotPersonalityNovice)
+	state.startBotSession("fresh", 10000)
+	state.botLogics["funded"] = NewBotLogicWithPersonality(BotPersonalityNovice)
+	state.botLogics["funded"].SetBalance(3000)
+	state.startBotSession("funded", 3000)
+	state.botSessions["funded"].WalletBalance = true
+
+	state.RestoreBotBankroll("fresh", nil, 7000, 4, 1)
+	state.RestoreBotBankroll("funded", nil, 7000, 4, 1)
+	if got := state.botLogics["fresh"].GetBalance(); got != 7000 || state.GetBotSession("fresh").StartBalance != 7000 {
+		t.Errorf("Expected stored balance to be resumed, got %d", got)
+	}
+	if got := state.botLogics["funded"].GetBalance(); got != 3000 {
+		t.Errorf("Expected wallet balance to be kept, got %d", got)
+	}
+}
@@ -0,0 +1,239 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Reasons of a bot fill decision
+const (
+	BotFillReasonOk             = "ok"
+	BotFillReasonNoHuman        = "no_human"
+	BotFillReasonPrivateTable   = "private_table"
+	BotFillReasonHumanWaiting   = "human_waiting"
+	BotFillReasonFreeHumanSeats = "free_seat_for_humans"
+	BotFillReasonMaxBots        = "max_bots"
+	BotFillReasonMinOccupied    = "min_occupied"
+)
+
+// BotFillHumanWaitWindow is how long a human rejected by a full table counts as waiting
+const BotFillHumanWaitWindow = 30 * time.Second
+
+// BotFillLogSize is the number of recent decisions kept for ops
+const BotFillLogSize = 200
+
+// BotFillPolicy declares how bots fill the seats of a table
+type BotFillPolicy struct {
+	// MinOccupied seats (humans and bots) while at least one human is seated
+	MinOccupied int `json:"min_occupied"`
+	// FreeSeatsForHumans are never taken by bots
+	FreeSeatsForHumans int `json:"free_seats_for_humans"`
+	// MaxBots at the table, 0 means no limit
+	MaxBots int `json:"max_bots"`
+	// AllowPrivate lets bots sit at password protected tables
+	AllowPrivate bool `json:"allow_private"`
+	// EvictForWaitingHuman removes a bot when a human was turned away
+	EvictForWaitingHuman bool `json:"evict_for_waiting_human"`
+}
+
+// DefaultBotFillPolicy applies to every stake without its own policy
+var DefaultBotFillPolicy = BotFillPolicy{
+	MinOccupied:          2,
+	FreeSeatsForHumans:   1,
+	EvictForWaitingHuman: true,
+}
+
+// botFillPolicies holds the fill policy of each stake level
+var botFillPolicies = newStakeTable(DefaultBotFillPolicy)
+
+// ParseBotFillPolicies parses a per stake config like {"0":{"min_occupied":2},"10000":{"max_bots":1}}
+func ParseBotFillPolicies(raw string) (map[int64]BotFillPolicy, error) {
+	policies := make(map[int64]BotFillPolicy)
+	if err := json.Unmarshal([]byte(raw), &policies); err != nil {
+		return nil, err
+	}
+	for stake, p := range policies {
+		if stake < 0 || p.MinOccupied < 0 || p.FreeSeatsForHumans < 0 || p.MaxBots < 0 {
+			return nil, fmt.Errorf("negative value in bot fill policy of stake %d", stake)
+		}
+	}
+	if len(policies) == 0 {
+		return nil, fmt.Errorf("no bot fill policy")
+	}
+	return policies, nil
+}
+
+// SetBotFillPolicies replaces the fill policies, nil restores the default
+func SetBotFillPolicies(policies map[int64]BotFillPolicy) {
+	botFillPolicies.Set(policies)
+}
+
+// BotFillPolicyForStake returns the policy of the highest stake level not above markUnit
+func BotFillPolicyForStake(markUnit int64) BotFillPolicy {
+	return botFillPolicies.For(markUnit)
+}
+
+// BotFillInput is the view of the table a policy is evaluated on
+type BotFillInput struct {
+	Humans       int
+	Bots         int
+	MaxSeats     int
+	Private      bool
+	HumanWaiting bool
+}
+
+// BotFillDecision is what a policy wants done with the bots of a table
+type BotFillDecision struct {
+	MatchId  string    `json:"match_id"`
+	MarkUnit int64     `json:"mark_unit"`
+	Phase    string    `json:"phase"`
+	Humans   int       `json:"humans"`
+	Bots     int       `json:"bots"`
+	Add      int       `json:"add"`
+	Remove   int       `json:"remove"`
+	Reason   string    `json:"reason"`
+	Time     time.Time `json:"time"`
+}
+
+// Evaluate decides how many bots to add or remove, rules are checked in order
+func (p BotFillPolicy) Evaluate(in BotFillInput) BotFillDecision {
+	d := BotFillDecision{Humans: in.Humans, Bots: in.Bots, Reason: BotFillReasonOk}
+	if in.Private && !p.AllowPrivate {
+		if in.Bots > 0 {
+			d.Remove, d.Reason = in.Bots, BotFillReasonPrivateTable
+		}
+		return d
+	}
+	if in.Humans == 0 {
+		// bots never keep a table alive on their own
+		d.Reason = BotFillReasonNoHuman
+		return d
+	}
+	if in.HumanWaiting && p.EvictForWaitingHuman && in.Bots > 0 {
+		d.Remove, d.Reason = 1, BotFillReasonHumanWaiting
+		return d
+	}
+	maxBots, reason := p.maxBots(in)
+	if in.Bots > maxBots {
+		d.Remove, d.Reason = in.Bots-maxBots, reason
+		return d
+	}
+	if missing := p.MinOccupied - in.Humans - in.Bots; missing > 0 {
+		if room := maxBots - in.Bots; missing > room {
+			missing = room
+		}
+		if missing > 0 {
+			d.Add, d.Reason = missing, BotFillReasonMinOccupied
+		}
+	}
+	return d
+}
+
+// maxBots returns how many bots may sit next to the humans of the table and the rule bounding it
+func (p BotFillPolicy) maxBots(in BotFillInput) (int, string) {
+	maxBots := in.MaxSeats - p.FreeSeatsForHumans - in.Humans
+	reason := BotFillReasonFreeHumanSeats
+	if p.MaxBots > 0 && p.MaxBots < maxBots {
+		maxBots, reason = p.MaxBots, BotFillReasonMaxBots
+	}
+	if maxBots < 0 {
+		maxBots = 0
+	}
+	return maxBots, reason
+}
+
+// BotRoom returns how many more bots the policy lets sit at the table,
+// bots seated outside of Evaluate are capped to it
+func (p BotFillPolicy) BotRoom(in BotFillInput) int {
+	if in.Private && !p.AllowPrivate || in.Humans == 0 || in.HumanWaiting && p.EvictForWaitingHuman {
+		return 0
+	}
+	maxBots, _ := p.maxBots(in)
+	if maxBots < in.Bots {
+		return 0
+	}
+	return maxBots - in.Bots
+}
+
+// BotsAboveMinOccupied returns how many bots may leave without taking the table below MinOccupied
+func (p BotFillPolicy) BotsAboveMinOccupied(in BotFillInput) int {
+	if in.Humans == 0 {
+		return in.Bots
+	}
+	n := in.Humans + in.Bots - p.MinOccupied
+	if n < 0 {
+		return 0
+	}
+	if n > in.Bots {
+		return in.Bots
+	}
+	return n
+}
+
+// BotFillPolicy returns the fill policy of the stake of the table
+func (s *MatchState) BotFillPolicy() BotFillPolicy {
+	return BotFillPolicyForStake(int64(s.Label.MarkUnit))
+}
+
+// BotFillInput returns the current view of the table for the fill policy
+func (s *MatchState) BotFillInput() BotFillInput {
+	return BotFillInput{
+		Humans:       s.GetPresenceSize() - len(s.Bots),
+		Bots:         len(s.Bots),
+		MaxSeats:     s.MaxPresences,
+		Private:      !s.Label.Open && s.Label.Password != "",
+		HumanWaiting: s.isHumanWaiting(),
+	}
+}
+
+// isHumanWaiting reports whether a spectator queues for a seat or a human was recently turned away
+func (s *MatchState) isHumanWaiting() bool {
+	if len(s.seatQueue) > 0 {
+		return true
+	}
+	return !s.humanWaitingAt.IsZero() && time.Since(s.humanWaitingAt) < BotFillHumanWaitWindow
+}
+
+// SetHumanWaiting records that a human was turned away because the table is full
+func (s *MatchState) SetHumanWaiting() {
+	s.humanWaitingAt = time.Now()
+}
+
+// ClearHumanWaiting forgets the waiting human once a seat was freed for them
+func (s *MatchState) ClearHumanWaiting() {
+	s.humanWaitingAt = time.Time{}
+}
+
+// botFillLog keeps the latest decisions of every table for ops
+type botFillLog struct {
+	mu        sync.Mutex
+	decisions []BotFillDecision
+}
+
+var BotFillLog = &botFillLog{}
+
+// Add records a decision, dropping the oldest once full
+func (l *botFillLog) Add(d BotFillDecision) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.decisions = append(l.decisions, d)
+	if len(l.decisions) > BotFillLogSize {
+		l.decisions = l.decisions[len(l.decisions)-BotFillLogSize:]
+	}
+}
+
+// Recent returns up to limit decisions, newest first
+func (l *botFillLog) Recent(limit int) []BotFillDecision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if limit <= 0 || limit > len(l.decisions) {
+		limit = len(l.decisions)
+	}
+	res := make([]BotFillDecision, 0, limit)
+	for i := len(l.decisions) - 1; i >= 0 && len(res) < limit; i-- {
+		res = append(res, l.decisions[i])
+	}
+	return res
+}
@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"testing"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func TestBotFillPolicyEvaluate(t *testing.T) {
+	policy := BotFillPolicy{MinOccupied: 3, FreeSeatsForHumans: 1, EvictForWaitingHuman: true}
+	testCases := []struct {
+		name   string
+		policy BotFillPolicy
+		in     BotFillInput
+		add    int
+		remove int
+		reason string
+	}{
+		{"fills to min occupied", policy, BotFillInput{Humans: 1, MaxSeats: 5}, 2, 0, BotFillReasonMinOccupied},
+		{"enough seats taken", policy, BotFillInput{Humans: 2, Bots: 1, MaxSeats: 5}, 0, 0, BotFillReasonOk},
+		{"keeps a seat for humans", policy, BotFillInput{Humans: 2, Bots: 3, MaxSeats: 5}, 0, 1, BotFillReasonFreeHumanSeats},
+		{"fill limited by free seat", policy, BotFillInput{Humans: 1, MaxSeats: 3}, 1, 0, BotFillReasonMinOccupied},
+		{"human waiting", policy, BotFillInput{Humans: 1, Bots: 2, MaxSeats: 5, HumanWaiting: true}, 0, 1, BotFillReasonHumanWaiting},
+		{"no bots on private tables", policy, BotFillInput{Humans: 1, Bots: 2, MaxSeats: 5, Private: true}, 0, 2, BotFillReasonPrivateTable},
+		{"private allowed", BotFillPolicy{MinOccupied: 2, AllowPrivate: true}, BotFillInput{Humans: 1, MaxSeats: 5, Private: true}, 1, 0, BotFillReasonMinOccupied},
+		{"no human no bots added", policy, BotFillInput{MaxSeats: 5}, 0, 0, BotFillReasonNoHuman},
+		{"max bots", BotFillPolicy{MinOccupied: 5, MaxBots: 1}, BotFillInput{Humans: 1, Bots: 2, MaxSeats: 5}, 0, 1, BotFillReasonMaxBots},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := tc.policy.Evaluate(tc.in)
+			if d.Add != tc.add || d.Remove != tc.remove || d.Reason != tc.reason {
+				t.Errorf("Expected add=%d remove=%d reason=%s, got add=%d remove=%d reason=%s",
+					tc.add, tc.remove, tc.reason, d.Add, d.Remove, d.Reason)
+			}
+		})
+	}
+}
+
+func TestBotFillPolicyBounds(t *testing.T) {
+	policy := BotFillPolicy{MinOccupied: 3, FreeSeatsForHumans: 1, EvictForWaitingHuman: true}
+	if n := policy.BotRoom(BotFillInput{Humans: 1, Bots: 1, MaxSeats: 5}); n != 2 {
+		t.Errorf("Expected room for 2 more bots, got %d", n)
+	}
+	for _, in := range []BotFillInput{
+		{MaxSeats: 5},
+		{Humans: 1, MaxSeats: 5, Private: true},
+		{Humans: 1, MaxSeats: 5, HumanWaiting: true},
+		{Humans: 2, Bots: 3, MaxSeats: 5},
+	} {
+		if n := policy.BotRoom(in); n != 0 {
+			t.Errorf("Expected no room for bots with %+v, got %d", in, n)
+		}
+	}
+	if n := policy.BotsAboveMinOccupied(BotFillInput{Humans: 1, Bots: 3, MaxSeats: 5}); n != 1 {
+		t.Errorf("Expected 1 bot free to leave, got %d", n)
+	}
+	if n := policy.BotsAboveMinOccupied(BotFillInput{Humans: 1, Bots: 2, MaxSeats: 5}); n != 0 {
+		t.Errorf("Expected bots to stay at min occupied, got %d", n)
+	}
+}
+
+func TestBotFillPolicyForStake(t *testing.T) {
+	policies, err := ParseBotFillPolicies(`{"0":{"min_occupied":2},"10000":{"max_bots":1}}`)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	SetBotFillPolicies(policies)
+	defer SetBotFillPolicies(nil)
+
+	if p := BotFillPolicyForStake(500); p.MinOccupied != 2 {
+		t.Errorf("Expected low stake policy, got %+v", p)
+	}
+	if p := BotFillPolicyForStake(50000); p.MaxBots != 1 {
+		t.Errorf("Expected high stake policy, got %+v", p)
+	}
+	if _, err := ParseBotFillPolicies(`{"0":{"max_bots":-1}}`); err == nil {
+		t.Errorf("Expected negative values to be rejected")
+	}
+}
+
+func TestBotFillInputPrivate(t *testing.T) {
+	state := NewMatchState(&pb.Match{MarkUnit: 100, Open: false, Password: "secret"})
+	if !state.BotFillInput().Private {
+		t.Errorf("Expected password table to be private")
+	}
+	state.SetHumanWaiting()
+	if !state.BotFillInput().HumanWaiting {
+		t.Errorf("Expected human waiting")
+	}
+	state.ClearHumanWaiting()
+	if state.BotFillInput().HumanWaiting {
+		t.Errorf("Expected waiting to be cleared")
+	}
+}
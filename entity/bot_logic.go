@@ -1,12 +1,20 @@
 package entity
 
 import (
+	"math"
 	"math/rand"
 	"time"
 
 	pb "github.com/nk-nigeria/cgp-common/proto"
 )
 
+const (
+	// InsuranceTrueCountIndex is the true count from which counting bots take insurance
+	InsuranceTrueCountIndex = 3
+	// MaxCountBetUnits caps the bet ramp of counting bots
+	MaxCountBetUnits = 8
+)
+
 // BlackjackBotLogic handles bot decision making for blackjack game
 type BlackjackBotLogic struct {
 	// Betting strategy configuration
@@ -21,6 +29,15 @@ type BlackjackBotLogic struct {
 	bettingPatterns map[string]int
 	// Game action history
 	actionHistory []*pb.BlackjackAction
+	// Hi-Lo counter, nil when the bot does not count cards
+	counter *HiLoCounter
+	// Playing style assigned when the bot is seated
+	personality BotPersonality
+	// Net chips of the last settled round
+	lastResult int64
+	// Probability of a human mistake on a decision
+	mistakeRate  float64
+	mistakeStats MistakeStats
 }
 
 // BettingStrategy defines how bot should bet
@@ -68,7 +85,57 @@ func NewBlackjackBotLogic() *BlackjackBotLogic {
 		bettingPatterns: make(map[string]int),
 		actionHistory:   make([]*pb.BlackjackAction, 0),
 		currentBalance:  10000, // Default balance
+		mistakeStats:    MistakeStats{ByKind: make(map[BotMistake]int)},
+	}
+}
+
+// NewCardCountingBotLogic creates a bot that keeps a Hi-Lo count of the shoe
+func NewCardCountingBotLogic() *BlackjackBotLogic {
+	b := NewBlackjackBotLogic()
+	b.EnableCardCounting(MaxCard)
+	return b
+}
+
+// EnableCardCounting makes the bot count cards for a shoe of shoeSize cards
+func (b *BlackjackBotLogic) EnableCardCounting(shoeSize int) {
+	b.counter = NewHiLoCounter(shoeSize)
+}
+
+// IsCardCounting returns whether the bot keeps a running count
+func (b *BlackjackBotLogic) IsCardCounting() bool {
+	return b.counter != nil
+}
+
+// ObserveCards adds dealt cards to the running count
+func (b *BlackjackBotLogic) ObserveCards(cards ...*pb.Card) {
+	if b.counter == nil {
+		return
+	}
+	b.counter.Observe(cards...)
+}
+
+// SetShoeRemaining syncs the number of undealt cards in the shoe
+func (b *BlackjackBotLogic) SetShoeRemaining(n int) {
+	if b.counter == nil {
+		return
+	}
+	b.counter.SetCardsRemaining(n)
+}
+
+// ResetCount clears the running count when the shoe is reshuffled
+func (b *BlackjackBotLogic) ResetCount(shoeSize int) {
+	if b.counter == nil {
+		return
+	}
+	b.counter.Reset(shoeSize)
+}
+
+// TrueCount returns the current true count, 0 for bots that don't count
+func (b *BlackjackBotLogic) TrueCount() float64 {
+	if b.counter == nil {
+		return 0
 	}
+	return b.counter.TrueCount()
 }
 
 // Reset resets the bot logic state
@@ -140,6 +207,11 @@ func (b *BlackjackBotLogic) DecideBetAmount() int64 {
 		}
 	}
 
+	// Counting bots ramp their bet with the true count
+	if b.counter != nil {
+		baseAmount *= b.countBetUnits()
+	}
+
 	// Ensure bet amount doesn't exceed maximum
 	maxAmount := int64(float64(b.currentBalance) * strategy.MaxBetPercentage)
 	if baseAmount > maxAmount {
@@ -189,6 +261,19 @@ func (b *BlackjackBotLogic) GenerateBotBet() *pb.BlackjackPlayerBet {
 
 // DecideGameAction decides what action to take during the game
 func (b *BlackjackBotLogic) DecideGameAction(playerHand *pb.BlackjackHand, dealerUpCard *pb.Card, legalActions []pb.BlackjackActionCode) pb.BlackjackActionCode {
+	action := b.optimalAction(playerHand, dealerUpCard, legalActions)
+	return b.maybeActionMistake(action, playerHand, dealerUpCard, legalActions)
+}
+
+// optimalAction is the action the bot's strategy considers best
+func (b *BlackjackBotLogic) optimalAction(playerHand *pb.BlackjackHand, dealerUpCard *pb.Card, legalActions []pb.BlackjackActionCode) pb.BlackjackActionCode {
+	// Counting bots deviate from basic strategy on index plays
+	if b.counter != nil {
+		if action, ok := b.indexPlay(playerHand, dealerUpCard, legalActions); ok {
+			return action
+		}
+	}
+
 	// Check for split first
 	if b.ShouldSplit(playerHand, dealerUpCard, legalActions) {
 		return pb.BlackjackActionCode_BLACKJACK_ACTION_SPLIT
@@ -200,20 +285,7 @@ func (b *BlackjackBotLogic) DecideGameAction(playerHand *pb.BlackjackHand, deale
 	}
 
 	// Basic strategy implementation
-	action := b.basicStrategy(playerHand, dealerUpCard, legalActions)
-
-	// Add some randomness based on risk tolerance
-	if rand.Intn(100) < b.riskTolerance {
-		// Higher risk tolerance - more likely to take risky actions
-		if action == pb.BlackjackActionCode_BLACKJACK_ACTION_STAY && rand.Intn(100) < 20 {
-			// 20% chance to hit instead of stay when risk tolerance is high
-			if b.containsAction(legalActions, pb.BlackjackActionCode_BLACKJACK_ACTION_HIT) {
-				action = pb.BlackjackActionCode_BLACKJACK_ACTION_HIT
-			}
-		}
-	}
-
-	return action
+	return b.basicStrategy(playerHand, dealerUpCard, legalActions)
 }
 
 // basicStrategy implements basic blackjack strategy
@@ -380,13 +452,36 @@ func (b *BlackjackBotLogic) getRecentBets(n int) []*pb.BlackjackPlayerBet {
 
 // wasLastBetLoss checks if the last bet was a loss
 func (b *BlackjackBotLogic) wasLastBetLoss() bool {
-	// This would need to be implemented based on game results
-	// For now, we'll assume 50% chance of loss
-	return rand.Intn(2) == 0
+	return b.lastResult < 0
+}
+
+// RecordResult stores the net chips won or lost in the last round
+func (b *BlackjackBotLogic) RecordResult(net int64) {
+	b.lastResult = net
+}
+
+// GetLastResult returns the net chips of the last settled round
+func (b *BlackjackBotLogic) GetLastResult() int64 {
+	return b.lastResult
+}
+
+// GetPersonality returns the personality the bot was created with
+func (b *BlackjackBotLogic) GetPersonality() BotPersonality {
+	return b.personality
 }
 
 // ShouldTakeInsurance determines if bot should take insurance
 func (b *BlackjackBotLogic) ShouldTakeInsurance(playerHand *pb.BlackjackHand, dealerUpCard *pb.Card) bool {
+	return b.maybeInsuranceMistake(b.optimalInsurance(playerHand, dealerUpCard), dealerUpCard)
+}
+
+// optimalInsurance is the insurance decision the bot's strategy considers best
+func (b *BlackjackBotLogic) optimalInsurance(playerHand *pb.BlackjackHand, dealerUpCard *pb.Card) bool {
+	// Insurance is a good bet once the true count reaches +3
+	if b.counter != nil {
+		return dealerUpCard.Rank == pb.CardRank_RANK_A && b.counter.TrueCount() >= InsuranceTrueCountIndex
+	}
+
 	// Basic insurance strategy
 	// Take insurance if dealer shows Ace and player has 20 or blackjack
 	if dealerUpCard.Rank == pb.CardRank_RANK_A {
@@ -394,13 +489,6 @@ func (b *BlackjackBotLogic) ShouldTakeInsurance(playerHand *pb.BlackjackHand, de
 			return true
 		}
 	}
-
-	// Add some randomness based on risk tolerance
-	if rand.Intn(100) < b.riskTolerance {
-		// Higher risk tolerance - more likely to take insurance
-		return rand.Intn(100) < 30 // 30% chance
-	}
-
 	return false
 }
 
@@ -526,6 +614,77 @@ func (b *BlackjackBotLogic) ShouldDoubleDown(playerHand *pb.BlackjackHand, deale
 	return false
 }
 
+// countBetUnits returns the bet multiplier for the current true count
+func (b *BlackjackBotLogic) countBetUnits() int64 {
+	tc := int64(math.Floor(b.counter.TrueCount()))
+	if tc <= 1 {
+		return 1
+	}
+	if tc > MaxCountBetUnits {
+		return MaxCountBetUnits
+	}
+	return tc
+}
+
+// indexPlay applies the Hi-Lo index deviations for the current true count
+func (b *BlackjackBotLogic) indexPlay(playerHand *pb.BlackjackHand, dealerUpCard *pb.Card, legalActions []pb.BlackjackActionCode) (pb.BlackjackActionCode, bool) {
+	if playerHand == nil || dealerUpCard == nil {
+		return 0, false
+	}
+	tc := b.counter.TrueCount()
+	dealerPoints := b.getCardValue(dealerUpCard)
+
+	// Split tens against 5 and 6 at high counts
+	if len(playerHand.Cards) == 2 && getCardPoint(playerHand.Cards[0].Rank) == 10 &&
+		getCardPoint(playerHand.Cards[1].Rank) == 10 &&
+		b.containsAction(legalActions, pb.BlackjackActionCode_BLACKJACK_ACTION_SPLIT) {
+		if (dealerPoints == 5 && tc >= 5) || (dealerPoints == 6 && tc >= 4) {
+			return pb.BlackjackActionCode_BLACKJACK_ACTION_SPLIT, true
+		}
+	}
+
+	// Remaining indexes only apply to hard totals
+	for _, card := range playerHand.Cards {
+		if card.Rank == pb.CardRank_RANK_A {
+			return 0, false
+		}
+	}
+
+	stand := pb.BlackjackActionCode_BLACKJACK_ACTION_STAY
+	canDouble := len(playerHand.Cards) == 2 && b.containsAction(legalActions, pb.BlackjackActionCode_BLACKJACK_ACTION_DOUBLE)
+	switch playerHand.Point {
+	case 16:
+		if dealerPoints == 10 && tc >= 0 {
+			return stand, true
+		}
+	case 15:
+		if dealerPoints == 10 && tc >= 4 {
+			return stand, true
+		}
+	case 13:
+		if dealerPoints == 2 && tc < -1 && b.containsAction(legalActions, pb.BlackjackActionCode_BLACKJACK_ACTION_HIT) {
+			return pb.BlackjackActionCode_BLACKJACK_ACTION_HIT, true
+		}
+	case 12:
+		if (dealerPoints == 3 && tc >= 2) || (dealerPoints == 2 && tc >= 3) {
+			return stand, true
+		}
+	case 11:
+		if canDouble && dealerPoints == 11 && tc >= 1 {
+			return pb.BlackjackActionCode_BLACKJACK_ACTION_DOUBLE, true
+		}
+	case 10:
+		if canDouble && (dealerPoints == 10 || dealerPoints == 11) && tc >= 4 {
+			return pb.BlackjackActionCode_BLACKJACK_ACTION_DOUBLE, true
+		}
+	case 9:
+		if canDouble && ((dealerPoints == 2 && tc >= 1) || (dealerPoints == 7 && tc >= 3)) {
+			return pb.BlackjackActionCode_BLACKJACK_ACTION_DOUBLE, true
+		}
+	}
+	return 0, false
+}
+
 // updateBettingPatterns updates the betting pattern statistics
 func (b *BlackjackBotLogic) updateBettingPatterns(betType pb.BlackjackBetCode) {
 	betTypeKey := betType.String()
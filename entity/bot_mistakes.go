@@ -0,0 +1,251 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sync"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+// BotMistake is a common human deviation from basic strategy
+type BotMistake string
+
+const (
+	MistakeNoSplitEights      BotMistake = "no_split_eights"
+	MistakeAlwaysInsurance    BotMistake = "always_insurance"
+	MistakeStandSoft17        BotMistake = "stand_soft_17"
+	MistakeStandStiffVsStrong BotMistake = "stand_stiff_vs_strong"
+	MistakeHitStiffVsWeak     BotMistake = "hit_stiff_vs_weak"
+	MistakeSkipDouble         BotMistake = "skip_double"
+)
+
+// DefaultBotMistakeWeights weights mistakes toward the ones real players make most
+var DefaultBotMistakeWeights = map[BotMistake]int{
+	MistakeNoSplitEights:      25,
+	MistakeAlwaysInsurance:    25,
+	MistakeStandSoft17:        20,
+	MistakeStandStiffVsStrong: 15,
+	MistakeSkipDouble:         10,
+	MistakeHitStiffVsWeak:     5,
+}
+
+// DefaultBotMistakeRates is the probability of deviating per personality,
+// counted on decisions where at least one mistake is possible
+var DefaultBotMistakeRates = map[BotPersonality]float64{
+	BotPersonalityCautious:    0.03,
+	BotPersonalityAggressive:  0.08,
+	BotPersonalityMartingale:  0.05,
+	BotPersonalityCardCounter: 0.01,
+	BotPersonalityNovice:      0.25,
+}
+
+var (
+	botMistakeMu      sync.RWMutex
+	botMistakeRates   = DefaultBotMistakeRates
+	botMistakeWeights = DefaultBotMistakeWeights
+)
+
+// ParseBotMistakeRates parses a rate config like {"novice":0.3,"cautious":0.02}
+func ParseBotMistakeRates(raw string) (map[BotPersonality]float64, error) {
+	rates := make(map[BotPersonality]float64)
+	if err := json.Unmarshal([]byte(raw), &rates); err != nil {
+		return nil, err
+	}
+	for p, r := range rates {
+		if !p.IsValid() {
+			return nil, fmt.Errorf("unknown bot personality %q", p)
+		}
+		if r < 0 || r > 1 {
+			return nil, fmt.Errorf("mistake rate of %q must be in [0, 1]", p)
+		}
+	}
+	return rates, nil
+}
+
+// SetBotMistakeRates overrides the mistake rate of the given personalities
+func SetBotMistakeRates(rates map[BotPersonality]float64) {
+	botMistakeMu.Lock()
+	defer botMistakeMu.Unlock()
+	merged := make(map[BotPersonality]float64, len(DefaultBotMistakeRates))
+	for p, r := range DefaultBotMistakeRates {
+		merged[p] = r
+	}
+	for p, r := range rates {
+		merged[p] = r
+	}
+	botMistakeRates = merged
+}
+
+// SetBotMistakeWeights replaces the relative weights of each mistake
+func SetBotMistakeWeights(weights map[BotMistake]int) {
+	botMistakeMu.Lock()
+	defer botMistakeMu.Unlock()
+	botMistakeWeights = weights
+}
+
+// BotMistakeRate returns the configured mistake rate of a personality
+func BotMistakeRate(p BotPersonality) float64 {
+	botMistakeMu.RLock()
+	defer botMistakeMu.RUnlock()
+	return botMistakeRates[p]
+}
+
+// MistakeStats measures how often a bot deviated from its optimal play
+type MistakeStats struct {
+	// Decisions is every action or insurance decision taken
+	Decisions int
+	// Opportunities is decisions where at least one mistake was possible
+	Opportunities int
+	// Mistakes is decisions where the bot deviated
+	Mistakes int
+	ByKind   map[BotMistake]int
+}
+
+// Rate returns the observed mistake rate over opportunities
+func (m MistakeStats) Rate() float64 {
+	if m.Opportunities == 0 {
+		return 0
+	}
+	return float64(m.Mistakes) / float64(m.Opportunities)
+}
+
+// actionMistakes lists the mistakes that would change the optimal action and what they'd play instead
+func actionMistakes(optimal pb.BlackjackActionCode, playerHand *pb.BlackjackHand, dealerUpCard *pb.Card, legalActions []pb.BlackjackActionCode) map[BotMistake]pb.BlackjackActionCode {
+	res := make(map[BotMistake]pb.BlackjackActionCode)
+	if playerHand == nil || dealerUpCard == nil {
+		return res
+	}
+	legal := func(a pb.BlackjackActionCode) bool {
+		for _, v := range legalActions {
+			if v == a {
+				return true
+			}
+		}
+		return false
+	}
+	dealer := getCardPoint(dealerUpCard.Rank)
+	if dealerUpCard.Rank == pb.CardRank_RANK_A {
+		dealer = 11
+	}
+	points := playerHand.Point
+	soft := playerHand.MaxPoint != playerHand.MinPoint
+	hit := pb.BlackjackActionCode_BLACKJACK_ACTION_HIT
+	stay := pb.BlackjackActionCode_BLACKJACK_ACTION_STAY
+
+	switch optimal {
+	case pb.BlackjackActionCode_BLACKJACK_ACTION_SPLIT:
+		cards := playerHand.Cards
+		if len(cards) == 2 && cards[0].Rank == pb.CardRank_RANK_8 && cards[1].Rank == pb.CardRank_RANK_8 {
+			// plays it as a hard 16
+			if dealer >= 7 {
+				res[MistakeNoSplitEights] = hit
+			} else {
+				res[MistakeNoSplitEights] = stay
+			}
+		}
+	case pb.BlackjackActionCode_BLACKJACK_ACTION_DOUBLE:
+		if legal(hit) {
+			res[MistakeSkipDouble] = hit
+		}
+		if soft && points == 17 {
+			res[MistakeStandSoft17] = stay
+		}
+	case hit:
+		if soft && points == 17 {
+			res[MistakeStandSoft17] = stay
+		}
+		if !soft && points >= 12 && points <= 16 && dealer >= 7 {
+			res[MistakeStandStiffVsStrong] = stay
+		}
+	case stay:
+		if !soft && points >= 12 && points <= 16 && dealer <= 6 && legal(hit) {
+			res[MistakeHitStiffVsWeak] = hit
+		}
+	}
+	return res
+}
+
+// rollMistake picks one of the possible mistakes with the bot's mistake rate
+func (b *BlackjackBotLogic) rollMistake(candidates []BotMistake) (BotMistake, bool) {
+	botMistakeMu.RLock()
+	total := 0
+	weights := make([]int, len(candidates))
+	for i, m := range candidates {
+		weights[i] = botMistakeWeights[m]
+		total += weights[i]
+	}
+	botMistakeMu.RUnlock()
+	if total == 0 {
+		return "", false
+	}
+	b.mistakeStats.Opportunities++
+	if rand.Float64() >= b.mistakeRate {
+		return "", false
+	}
+	n := rand.Intn(total)
+	for i, m := range candidates {
+		n -= weights[i]
+		if n < 0 {
+			b.mistakeStats.Mistakes++
+			b.mistakeStats.ByKind[m]++
+			return m, true
+		}
+	}
+	return "", false
+}
+
+// maybeActionMistake may replace the optimal action by a human mistake
+func (b *BlackjackBotLogic) maybeActionMistake(optimal pb.BlackjackActionCode, playerHand *pb.BlackjackHand, dealerUpCard *pb.Card, legalActions []pb.BlackjackActionCode) pb.BlackjackActionCode {
+	b.mistakeStats.Decisions++
+	options := actionMistakes(optimal, playerHand, dealerUpCard, legalActions)
+	if len(options) == 0 {
+		return optimal
+	}
+	// sorted so the weighted pick doesn't depend on map order
+	candidates := make([]BotMistake, 0, len(options))
+	for _, m := range []BotMistake{
+		MistakeNoSplitEights, MistakeStandSoft17, MistakeStandStiffVsStrong,
+		MistakeHitStiffVsWeak, MistakeSkipDouble,
+	} {
+		if _, ok := options[m]; ok {
+			candidates = append(candidates, m)
+		}
+	}
+	if m, ok := b.rollMistake(candidates); ok {
+		return options[m]
+	}
+	return optimal
+}
+
+// maybeInsuranceMistake may take insurance the bot should have declined
+func (b *BlackjackBotLogic) maybeInsuranceMistake(optimal bool, dealerUpCard *pb.Card) bool {
+	b.mistakeStats.Decisions++
+	if optimal || dealerUpCard == nil || dealerUpCard.Rank != pb.CardRank_RANK_A {
+		return optimal
+	}
+	_, ok := b.rollMistake([]BotMistake{MistakeAlwaysInsurance})
+	return ok
+}
+
+// SetMistakeRate sets the probability of deviating from optimal play
+func (b *BlackjackBotLogic) SetMistakeRate(rate float64) {
+	b.mistakeRate = rate
+}
+
+// GetMistakeRate returns the configured mistake rate
+func (b *BlackjackBotLogic) GetMistakeRate() float64 {
+	return b.mistakeRate
+}
+
+// GetMistakeStats returns the decisions and mistakes made so far
+func (b *BlackjackBotLogic) GetMistakeStats() MistakeStats {
+	byKind := make(map[BotMistake]int, len(b.mistakeStats.ByKind))
+	for k, v := range b.mistakeStats.ByKind {
+		byKind[k] = v
+	}
+	stats := b.mistakeStats
+	stats.ByKind = byKind
+	return stats
+}
@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"math"
+	"testing"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func TestBotMistakeRateSimulation(t *testing.T) {
+	ten := &pb.Card{Rank: pb.CardRank_RANK_K}
+	ace := &pb.Card{Rank: pb.CardRank_RANK_A}
+	hit := []pb.BlackjackActionCode{
+		pb.BlackjackActionCode_BLACKJACK_ACTION_HIT,
+		pb.BlackjackActionCode_BLACKJACK_ACTION_STAY,
+	}
+	split := append([]pb.BlackjackActionCode{pb.BlackjackActionCode_BLACKJACK_ACTION_SPLIT}, hit...)
+	hard16 := &pb.BlackjackHand{
+		Cards: []*pb.Card{{Rank: pb.CardRank_RANK_10}, {Rank: pb.CardRank_RANK_6}},
+		Point: 16, MinPoint: 16, MaxPoint: 16,
+	}
+	eights := &pb.BlackjackHand{
+		Cards: []*pb.Card{{Rank: pb.CardRank_RANK_8}, {Rank: pb.CardRank_RANK_8}},
+		Point: 16, MinPoint: 16, MaxPoint: 16,
+	}
+	hard15 := &pb.BlackjackHand{Point: 15, MinPoint: 15, MaxPoint: 15}
+
+	const rounds = 20000
+	novice := NewBotLogicWithPersonality(BotPersonalityNovice)
+	for i := 0; i < rounds; i++ {
+		novice.DecideGameAction(hard16, ten, hit)
+		novice.DecideGameAction(eights, ten, split)
+		novice.ShouldTakeInsurance(hard15, ace)
+	}
+	stats := novice.GetMistakeStats()
+	if stats.Decisions != 3*rounds || stats.Opportunities != 3*rounds {
+		t.Fatalf("Expected %d decisions and opportunities, got %d and %d", 3*rounds, stats.Decisions, stats.Opportunities)
+	}
+	expected := BotMistakeRate(BotPersonalityNovice)
+	if math.Abs(stats.Rate()-expected) > 0.02 {
+		t.Errorf("Expected mistake rate close to %.2f, got %.3f", expected, stats.Rate())
+	}
+	for _, m := range []BotMistake{MistakeStandStiffVsStrong, MistakeNoSplitEights, MistakeAlwaysInsurance} {
+		if stats.ByKind[m] == 0 {
+			t.Errorf("Expected some %s mistakes", m)
+		}
+	}
+
+	perfect := NewBotLogicWithPersonality(BotPersonalityNovice)
+	perfect.SetMistakeRate(0)
+	for i := 0; i < 1000; i++ {
+		if perfect.DecideGameAction(hard16, ten, hit) != pb.BlackjackActionCode_BLACKJACK_ACTION_HIT {
+			t.Fatalf("Expected bot without mistakes to hit 16 vs 10")
+		}
+		if perfect.ShouldTakeInsurance(hard15, ace) {
+			t.Fatalf("Expected bot without mistakes to decline insurance on 15")
+		}
+	}
+	if perfect.GetMistakeStats().Mistakes != 0 {
+		t.Errorf("Expected no mistakes with rate 0")
+	}
+}
+
+func TestParseBotMistakeRates(t *testing.T) {
+	defer SetBotMistakeRates(nil)
+
+	rates, err := ParseBotMistakeRates(`{"novice":0.4}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	SetBotMistakeRates(rates)
+	if BotMistakeRate(BotPersonalityNovice) != 0.4 {
+		t.Errorf("Expected novice rate 0.4, got %f", BotMistakeRate(BotPersonalityNovice))
+	}
+	if BotMistakeRate(BotPersonalityCautious) != DefaultBotMistakeRates[BotPersonalityCautious] {
+		t.Errorf("Expected other personalities to keep their default rate")
+	}
+	for _, raw := range []string{`{"novice":1.5}`, `{"gambler":0.1}`} {
+		if _, err := ParseBotMistakeRates(raw); err == nil {
+			t.Errorf("Expected error for %s", raw)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+// BotPersonality is the playing style a bot keeps while it stays seated
+type BotPersonality string
+
+const (
+	BotPersonalityCautious    BotPersonality = "cautious"
+	BotPersonalityAggressive  BotPersonality = "aggressive"
+	BotPersonalityMartingale  BotPersonality = "martingale"
+	BotPersonalityCardCounter BotPersonality = "card_counter"
+	BotPersonalityNovice      BotPersonality = "novice"
+)
+
+// DefaultBotPersonalityWeights is the personality mix used when no config is provided
+var DefaultBotPersonalityWeights = map[BotPersonality]int{
+	BotPersonalityCautious:    30,
+	BotPersonalityAggressive:  20,
+	BotPersonalityMartingale:  15,
+	BotPersonalityCardCounter: 10,
+	BotPersonalityNovice:      25,
+}
+
+// Ordered so that weighted picks don't depend on map iteration order
+var botPersonalities = []BotPersonality{
+	BotPersonalityCautious,
+	BotPersonalityAggressive,
+	BotPersonalityMartingale,
+	BotPersonalityCardCounter,
+	BotPersonalityNovice,
+}
+
+var (
+	botPersonalityMu      sync.RWMutex
+	botPersonalityWeights = DefaultBotPersonalityWeights
+)
+
+// IsValid reports whether p is a known personality
+func (p BotPersonality) IsValid() bool {
+	for _, v := range botPersonalities {
+		if v == p {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseBotPersonalityWeights parses a weight config like {"cautious":30,"novice":70}
+func ParseBotPersonalityWeights(raw string) (map[BotPersonality]int, error) {
+	weights := make(map[BotPersonality]int)
+	if err := json.Unmarshal([]byte(raw), &weights); err != nil {
+		return nil, err
+	}
+	total := 0
+	for p, w := range weights {
+		if !p.IsValid() {
+			return nil, fmt.Errorf("unknown bot personality %q", p)
+		}
+		if w < 0 {
+			return nil, fmt.Errorf("negative weight for bot personality %q", p)
+		}
+		total += w
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("bot personality weights sum to zero")
+	}
+	return weights, nil
+}
+
+// SetBotPersonalityWeights replaces the weights used when seating new bots
+func SetBotPersonalityWeights(weights map[BotPersonality]int) {
+	botPersonalityMu.Lock()
+	defer botPersonalityMu.Unlock()
+	botPersonalityWeights = weights
+}
+
+// PickBotPersonality draws a personality from the configured weights
+func PickBotPersonality() BotPersonality {
+	botPersonalityMu.RLock()
+	defer botPersonalityMu.RUnlock()
+	total := 0
+	for _, p := range botPersonalities {
+		total += botPersonalityWeights[p]
+	}
+	if total <= 0 {
+		return BotPersonalityCautious
+	}
+	n := rand.Intn(total)
+	for _, p := range botPersonalities {
+		n -= botPersonalityWeights[p]
+		if n < 0 {
+			return p
+		}
+	}
+	return BotPersonalityCautious
+}
+
+// NewBotLogicWithPersonality creates a bot logic tuned for the given personality
+func NewBotLogicWithPersonality(p BotPersonality) *BlackjackBotLogic {
+	b := NewBlackjackBotLogic()
+	b.personality = p
+	strategy := &b.bettingStrategy.BetAmountStrategy
+	switch p {
+	case BotPersonalityCautious:
+		b.SetRiskLevel("conservative")
+		strategy.ProgressiveBetting = false
+	case BotPersonalityAggressive:
+		b.SetRiskLevel("aggressive")
+		strategy.ProgressiveBetting = false
+	case BotPersonalityMartingale:
+		b.SetRiskLevel("moderate")
+		strategy.ProgressiveBetting = true
+		strategy.MartingaleMultiplier = 2.0
+	case BotPersonalityCardCounter:
+		// Counters play close to basic strategy and only raise with the count
+		b.SetRiskLevel("conservative")
+		strategy.ProgressiveBetting = false
+		b.EnableCardCounting(MaxCard)
+	case BotPersonalityNovice:
+		b.SetRiskLevel("moderate")
+		strategy.ProgressiveBetting = false
+	}
+	b.SetMistakeRate(BotMistakeRate(p))
+	return b
+}
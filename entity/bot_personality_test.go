@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"testing"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func TestParseBotPersonalityWeights(t *testing.T) {
+	weights, err := ParseBotPersonalityWeights(`{"cautious":1,"novice":3}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if weights[BotPersonalityNovice] != 3 {
+		t.Errorf("Expected novice weight 3, got %d", weights[BotPersonalityNovice])
+	}
+
+	for _, raw := range []string{`{"gambler":10}`, `{"cautious":-1,"novice":2}`, `{"cautious":0}`, `not json`} {
+		if _, err := ParseBotPersonalityWeights(raw); err == nil {
+			t.Errorf("Expected error for %s", raw)
+		}
+	}
+}
+
+func TestPickBotPersonality(t *testing.T) {
+	defer SetBotPersonalityWeights(DefaultBotPersonalityWeights)
+
+	SetBotPersonalityWeights(map[BotPersonality]int{BotPersonalityMartingale: 1})
+	for i := 0; i < 20; i++ {
+		if p := PickBotPersonality(); p != BotPersonalityMartingale {
+			t.Fatalf("Expected martingale, got %s", p)
+		}
+	}
+}
+
+func TestBotPersonalityProfiles(t *testing.T) {
+	if !NewBotLogicWithPersonality(BotPersonalityCardCounter).IsCardCounting() {
+		t.Errorf("Expected card counter personality to count cards")
+	}
+	if NewBotLogicWithPersonality(BotPersonalityCautious).GetRiskLevel() != "conservative" {
+		t.Errorf("Expected cautious personality to be conservative")
+	}
+	if NewBotLogicWithPersonality(BotPersonalityAggressive).GetRiskLevel() != "aggressive" {
+		t.Errorf("Expected aggressive personality to be aggressive")
+	}
+
+	// Martingale doubles only after a real loss
+	martingale := NewBotLogicWithPersonality(BotPersonalityMartingale)
+	martingale.SetBalance(100000)
+	first := martingale.GenerateBotBet().First
+	martingale.RecordResult(first)
+	if bet := martingale.DecideBetAmount(); bet != first {
+		t.Errorf("Expected base bet %d after a win, got %d", first, bet)
+	}
+	martingale.RecordResult(-first)
+	if bet := martingale.DecideBetAmount(); bet != first*2 {
+		t.Errorf("Expected doubled bet %d after a loss, got %d", first*2, bet)
+	}
+}
+
+func TestMatchStateBotLogicPerBot(t *testing.T) {
+	state := NewMatchState(&pb.Match{})
+	state.botLogics["bot1"] = NewBotLogicWithPersonality(BotPersonalityCautious)
+	state.botLogics["bot2"] = NewBotLogicWithPersonality(BotPersonalityAggressive)
+
+	state.RecordBotResults(&pb.BalanceResult{Updates: []*pb.BalanceUpdate{
+		{UserId: "bot1", AmountChipCurrent: 5000, TotalChipInMatch: -1000},
+		{UserId: "bot2", AmountChipCurrent: 9000, TotalChipInMatch: 2000},
+	}})
+	if state.GetBotLogic("bot1").GetBalance() != 5000 || state.GetBotLogic("bot2").GetBalance() != 9000 {
+		t.Errorf("Expected each bot to keep its own balance")
+	}
+	if state.GetBotLogic("bot1").GetLastResult() != -1000 {
+		t.Errorf("Expected bot1 last result -1000, got %d", state.GetBotLogic("bot1").GetLastResult())
+	}
+	if state.BotResults["bot2"] != 2000 {
+		t.Errorf("Expected bot2 total result 2000, got %d", state.BotResults["bot2"])
+	}
+}
@@ -0,0 +1,36 @@
+package entity
+
+import pb "github.com/nk-nigeria/cgp-common/proto"
+
+// BotPnl is the result of the bots of a table, seen from the bots' side:
+// a positive Net is money the house lost to its own bots
+type BotPnl struct {
+	Rounds int64
+	Bet    int64
+	Win    int64
+	Net    int64
+}
+
+// IsBot returns whether userId is a bot seated at the table
+func (s *MatchState) IsBot(userId string) bool {
+	_, ok := s.botLogics[userId]
+	return ok
+}
+
+// BotRoundPnl sums the balance updates of bots that played the round
+func (s *MatchState) BotRoundPnl(balanceResult *pb.BalanceResult) BotPnl {
+	pnl := BotPnl{}
+	if balanceResult == nil {
+		return pnl
+	}
+	for _, update := range balanceResult.Updates {
+		if !s.IsBot(update.UserId) || update.AmoutChipBet == 0 {
+			continue
+		}
+		pnl.Rounds++
+		pnl.Bet += update.AmoutChipBet
+		pnl.Win += update.AmountChipAdd
+		pnl.Net += update.TotalChipInMatch
+	}
+	return pnl
+}
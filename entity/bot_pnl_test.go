@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"testing"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func TestBotRoundPnl(t *testing.T) {
+	state := NewMatchState(&pb.Match{MarkUnit: 100})
+	state.botLogics["bot1"] = NewBotLogicWithPersonality(BotPersonalityNovice)
+	state.botLogics["bot2"] = NewBotLogicWithPersonality(BotPersonalityCautious)
+	state.botLogics["bot3"] = NewBotLogicWithPersonality(BotPersonalityCautious)
+
+	if !state.IsBot("bot1") || state.IsBot("human") {
+		t.Fatalf("Unexpected bot detection")
+	}
+
+	pnl := state.BotRoundPnl(&pb.BalanceResult{Updates: []*pb.BalanceUpdate{
+		{UserId: "bot1", AmoutChipBet: 200, AmountChipAdd: 400, TotalChipInMatch: 200},
+		{UserId: "bot2", AmoutChipBet: 300, TotalChipInMatch: -300},
+		{UserId: "bot3"},
+		{UserId: "human", AmoutChipBet: 1000, AmountChipAdd: 1900, TotalChipInMatch: 900},
+	}})
+	expected := BotPnl{Rounds: 2, Bet: 500, Win: 400, Net: -100}
+	if pnl != expected {
+		t.Errorf("Expected %+v, got %+v", expected, pnl)
+	}
+	if got := state.BotRoundPnl(nil); got != (BotPnl{}) {
+		t.Errorf("Expected empty pnl for nil result, got %+v", got)
+	}
+}
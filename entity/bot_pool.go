@@ -0,0 +1,115 @@
+package entity
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// BotLoaderMinChip is the wallet a bot needs to be picked by the BotLoader
+	BotLoaderMinChip = 100000
+
+	// Wallet band of a bot in multiples of the table MarkUnit
+	BotWalletMinUnits    = 50
+	BotWalletTargetUnits = 200
+	BotWalletMaxUnits    = 1000
+)
+
+// BotWalletBand is the wallet range a bot is kept in for a stake level
+type BotWalletBand struct {
+	Min    int64
+	Target int64
+	Max    int64
+}
+
+// BotWalletBandForStake returns the wallet band of bots playing at markUnit
+func BotWalletBandForStake(markUnit int64) BotWalletBand {
+	band := BotWalletBand{
+		Min:    markUnit * BotWalletMinUnits,
+		Target: markUnit * BotWalletTargetUnits,
+		Max:    markUnit * BotWalletMaxUnits,
+	}
+	// a bot below the loader minimum is never picked again
+	if band.Min < BotLoaderMinChip {
+		band.Min = BotLoaderMinChip
+	}
+	if band.Target < band.Min {
+		band.Target = band.Min
+	}
+	if band.Max < band.Target {
+		band.Max = band.Target
+	}
+	return band
+}
+
+// Adjustment returns the chips to add (or remove when negative) to bring balance back to target,
+// 0 while balance stays inside the band
+func (b BotWalletBand) Adjustment(balance int64) int64 {
+	if balance >= b.Min && balance <= b.Max {
+		return 0
+	}
+	return b.Target - balance
+}
+
+type seatedBot struct {
+	MatchId  string
+	MarkUnit int64
+	Since    time.Time
+}
+
+// BotAccountPool tracks which bot accounts are seated at a table across all matches
+type BotAccountPool struct {
+	mu     sync.RWMutex
+	seated map[string]seatedBot
+}
+
+// BotPoolStats summarizes seated bots
+type BotPoolStats struct {
+	Seated  int
+	ByStake map[int64]int
+	ByMatch map[string]int
+}
+
+var BotPool = NewBotAccountPool()
+
+func NewBotAccountPool() *BotAccountPool {
+	return &BotAccountPool{seated: make(map[string]seatedBot)}
+}
+
+// Seat marks a bot as playing at matchId
+func (p *BotAccountPool) Seat(userId, matchId string, markUnit int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.seated[userId] = seatedBot{MatchId: matchId, MarkUnit: markUnit, Since: time.Now()}
+}
+
+// Release marks a bot as free again
+func (p *BotAccountPool) Release(userId string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.seated, userId)
+}
+
+// IsSeated returns whether the bot is playing at any table
+func (p *BotAccountPool) IsSeated(userId string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	_, ok := p.seated[userId]
+	return ok
+}
+
+// Stats returns the number of seated bots per stake and per match
+func (p *BotAccountPool) Stats() BotPoolStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	stats := BotPoolStats{
+		Seated:  len(p.seated),
+		ByStake: make(map[int64]int),
+		ByMatch: make(map[string]int),
+	}
+	for _, v := range p.seated {
+		stats.ByStake[v.MarkUnit]++
+		stats.ByMatch[v.MatchId]++
+	}
+	return stats
+}
@@ -0,0 +1,64 @@
+package entity
+
+import "testing"
+
+func TestBotWalletBandForStake(t *testing.T) {
+	band := BotWalletBandForStake(1000)
+	if band.Min != 100000 || band.Target != 200000 || band.Max != 1000000 {
+		t.Errorf("Unexpected band %+v", band)
+	}
+
+	// low stakes never drop below what the loader needs
+	band = BotWalletBandForStake(100)
+	if band.Min != BotLoaderMinChip {
+		t.Errorf("Expected min %d, got %d", BotLoaderMinChip, band.Min)
+	}
+	if band.Target < band.Min || band.Max < band.Target {
+		t.Errorf("Band out of order %+v", band)
+	}
+}
+
+func TestBotWalletBandAdjustment(t *testing.T) {
+	band := BotWalletBand{Min: 100, Target: 200, Max: 1000}
+	testCases := []struct {
+		name     string
+		balance  int64
+		expected int64
+	}{
+		{"inside band", 500, 0},
+		{"at min", 100, 0},
+		{"at max", 1000, 0},
+		{"below min tops up", 40, 160},
+		{"above max trims", 1500, -1300},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := band.Adjustment(tc.balance); got != tc.expected {
+				t.Errorf("Expected adjustment %d, got %d", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestBotAccountPool(t *testing.T) {
+	pool := NewBotAccountPool()
+	pool.Seat("bot1", "m1", 100)
+	pool.Seat("bot2", "m1", 100)
+	pool.Seat("bot3", "m2", 1000)
+
+	stats := pool.Stats()
+	if stats.Seated != 3 || stats.ByMatch["m1"] != 2 || stats.ByStake[1000] != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+
+	pool.Release("bot1")
+	if pool.IsSeated("bot1") {
+		t.Errorf("Expected bot1 to be released")
+	}
+	if !pool.IsSeated("bot2") {
+		t.Errorf("Expected bot2 to stay seated")
+	}
+	if got := pool.Stats().ByMatch["m1"]; got != 1 {
+		t.Errorf("Expected 1 bot in m1, got %d", got)
+	}
+}
@@ -0,0 +1,201 @@
+package entity
+
+import (
+	"math/rand"
+	"time"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+// DecisionDifficulty describes how much a human would hesitate on a decision
+type DecisionDifficulty int
+
+const (
+	DecisionEasy DecisionDifficulty = iota
+	DecisionNormal
+	DecisionHard
+)
+
+const (
+	// BotDecisionSafetyMargin keeps scheduled decisions away from the end of the countdown
+	BotDecisionSafetyMargin = 1 * time.Second
+	// MinBotThinkTime is the fastest a bot ever reacts
+	MinBotThinkTime = 400 * time.Millisecond
+)
+
+type thinkTimeRange struct {
+	min time.Duration
+	max time.Duration
+}
+
+// Base think time of a normal decision per personality
+var botThinkTimes = map[BotPersonality]thinkTimeRange{
+	BotPersonalityCautious:    {1500 * time.Millisecond, 3000 * time.Millisecond},
+	BotPersonalityAggressive:  {600 * time.Millisecond, 1500 * time.Millisecond},
+	BotPersonalityMartingale:  {1000 * time.Millisecond, 2200 * time.Millisecond},
+	BotPersonalityCardCounter: {1200 * time.Millisecond, 2500 * time.Millisecond},
+	BotPersonalityNovice:      {1500 * time.Millisecond, 4000 * time.Millisecond},
+}
+
+var defaultThinkTime = thinkTimeRange{1000 * time.Millisecond, 2500 * time.Millisecond}
+
+// Multiplier applied to the base think time per difficulty
+var difficultyFactor = map[DecisionDifficulty]float64{
+	DecisionEasy:   0.6,
+	DecisionNormal: 1.0,
+	DecisionHard:   1.8,
+}
+
+// ClassifyDecision rates how hard the decision on playerHand is
+func ClassifyDecision(playerHand *pb.BlackjackHand, dealerUpCard *pb.Card, legalActions []pb.BlackjackActionCode) DecisionDifficulty {
+	for _, a := range legalActions {
+		if a == pb.BlackjackActionCode_BLACKJACK_ACTION_SPLIT {
+			return DecisionHard
+		}
+	}
+	if playerHand == nil || dealerUpCard == nil {
+		return DecisionNormal
+	}
+	points := playerHand.Point
+	dealer := getCardPoint(dealerUpCard.Rank)
+	if dealerUpCard.Rank == pb.CardRank_RANK_A {
+		dealer = 11
+	}
+	switch {
+	case points <= 8 || points >= 19:
+		return DecisionEasy
+	case points >= 12 && points <= 16 && dealer >= 7:
+		// stiff hand against a strong dealer card
+		return DecisionHard
+	case (points == 10 || points == 11) && dealer >= 10:
+		// double or just hit
+		return DecisionHard
+	}
+	return DecisionNormal
+}
+
+// ThinkTime draws a randomized think time for the personality and difficulty
+func ThinkTime(p BotPersonality, difficulty DecisionDifficulty) time.Duration {
+	r, ok := botThinkTimes[p]
+	if !ok {
+		r = defaultThinkTime
+	}
+	d := r.min
+	if r.max > r.min {
+		d += time.Duration(rand.Int63n(int64(r.max - r.min)))
+	}
+	d = time.Duration(float64(d) * difficultyFactor[difficulty])
+	if d < MinBotThinkTime {
+		d = MinBotThinkTime
+	}
+	return d
+}
+
+// ClampThinkTime keeps a think time inside the remaining countdown
+func ClampThinkTime(d time.Duration, remain time.Duration) time.Duration {
+	limit := remain - BotDecisionSafetyMargin
+	if limit < 0 {
+		limit = 0
+	}
+	if d > limit {
+		return limit
+	}
+	return d
+}
+
+type scheduledDecision struct {
+	userId string
+	at     time.Time
+	// fire returns true when it queued an action the engine has yet to accept
+	fire func() bool
+}
+
+// BotDecisionScheduler delays bot decisions so they don't act on the tick their turn starts.
+// Each decision is identified by a key and runs at most once until Reset, a decision whose
+// action the engine refused can be scheduled again.
+type BotDecisionScheduler struct {
+	pending map[string]*scheduledDecision
+	// sent holds the user of each fired decision waiting for its action to be accepted
+	sent map[string]string
+	done map[string]bool
+	now  func() time.Time
+}
+
+func NewBotDecisionScheduler() *BotDecisionScheduler {
+	return &BotDecisionScheduler{
+		pending: make(map[string]*scheduledDecision),
+		sent:    make(map[string]string),
+		done:    make(map[string]bool),
+		now:     time.Now,
+	}
+}
+
+// Schedule queues fire to run after delay, returns false if key is already scheduled or done
+func (d *BotDecisionScheduler) Schedule(userId, key string, delay time.Duration, fire func() bool) bool {
+	if d.IsScheduled(key) {
+		return false
+	}
+	d.pending[key] = &scheduledDecision{
+		userId: userId,
+		at:     d.now().Add(delay),
+		fire:   fire,
+	}
+	return true
+}
+
+// IsScheduled returns whether key is pending, waiting for its action or already done
+func (d *BotDecisionScheduler) IsScheduled(key string) bool {
+	_, sent := d.sent[key]
+	return d.done[key] || sent || d.pending[key] != nil
+}
+
+// RunDue fires every decision whose think time has elapsed
+func (d *BotDecisionScheduler) RunDue() int {
+	now := d.now()
+	fired := 0
+	for key, v := range d.pending {
+		if now.Before(v.at) {
+			continue
+		}
+		delete(d.pending, key)
+		if v.fire() {
+			d.sent[key] = v.userId
+		} else {
+			d.done[key] = true
+		}
+		fired++
+	}
+	return fired
+}
+
+// Accept marks the sent decisions of userId done once the engine applied their action
+func (d *BotDecisionScheduler) Accept(userId string) {
+	for key, owner := range d.sent {
+		if owner == userId {
+			delete(d.sent, key)
+			d.done[key] = true
+		}
+	}
+}
+
+// Settle forgets the sent decisions whose action was not accepted so they are decided again,
+// called once the queued actions have been processed
+func (d *BotDecisionScheduler) Settle() {
+	d.sent = make(map[string]string)
+}
+
+// Cancel drops every pending decision of userId
+func (d *BotDecisionScheduler) Cancel(userId string) {
+	for key, v := range d.pending {
+		if v.userId == userId {
+			delete(d.pending, key)
+		}
+	}
+}
+
+// Reset clears all pending and done decisions, called on each new game
+func (d *BotDecisionScheduler) Reset() {
+	d.pending = make(map[string]*scheduledDecision)
+	d.sent = make(map[string]string)
+	d.done = make(map[string]bool)
+}
@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func TestClassifyDecision(t *testing.T) {
+	ten := &pb.Card{Rank: pb.CardRank_RANK_K}
+	testCases := []struct {
+		name         string
+		point        int32
+		legalActions []pb.BlackjackActionCode
+		expected     DecisionDifficulty
+	}{
+		{"16 vs 10 is hard", 16, nil, DecisionHard},
+		{"split is hard", 16, []pb.BlackjackActionCode{pb.BlackjackActionCode_BLACKJACK_ACTION_SPLIT}, DecisionHard},
+		{"20 is easy", 20, nil, DecisionEasy},
+		{"17 is normal", 17, nil, DecisionNormal},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hand := &pb.BlackjackHand{Point: tc.point}
+			if got := ClassifyDecision(hand, ten, tc.legalActions); got != tc.expected {
+				t.Errorf("Expected difficulty %d, got %d", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestThinkTime(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		easy := ThinkTime(BotPersonalityNovice, DecisionEasy)
+		hard := ThinkTime(BotPersonalityNovice, DecisionHard)
+		if hard <= easy {
+			t.Fatalf("Expected hard decision to take longer, easy %v hard %v", easy, hard)
+		}
+	}
+	if d := ClampThinkTime(5*time.Second, 3*time.Second); d != 3*time.Second-BotDecisionSafetyMargin {
+		t.Errorf("Expected think time clamped inside countdown, got %v", d)
+	}
+	if d := ClampThinkTime(5*time.Second, 0); d != 0 {
+		t.Errorf("Expected zero think time when countdown is over, got %v", d)
+	}
+}
+
+func TestBotDecisionScheduler(t *testing.T) {
+	now := time.Now()
+	scheduler := NewBotDecisionScheduler()
+	scheduler.now = func() time.Time { return now }
+
+	fired := 0
+	if !scheduler.Schedule("bot1", "bot1:insurance", 2*time.Second, func() bool { fired++; return false }) {
+		t.Fatalf("Expected decision to be scheduled")
+	}
+	if scheduler.Schedule("bot1", "bot1:insurance", 0, func() bool { fired++; return false }) {
+		t.Errorf("Expected duplicate decision to be rejected")
+	}
+	if scheduler.RunDue() != 0 || fired != 0 {
+		t.Errorf("Expected decision to wait for its think time")
+	}
+	now = now.Add(2 * time.Second)
+	if scheduler.RunDue() != 1 || fired != 1 {
+		t.Errorf("Expected decision to fire after its think time")
+	}
+	if scheduler.Schedule("bot1", "bot1:insurance", 0, func() bool { fired++; return false }) {
+		t.Errorf("Expected a done decision not to run again before reset")
+	}
+
+	scheduler.Schedule("bot2", "bot2:action", time.Second, func() bool { fired++; return false })
+	scheduler.Cancel("bot2")
+	now = now.Add(time.Second)
+	if scheduler.RunDue() != 0 {
+		t.Errorf("Expected cancelled decision not to fire")
+	}
+
+	scheduler.Reset()
+	if !scheduler.Schedule("bot1", "bot1:insurance", 0, func() bool { fired++; return false }) {
+		t.Errorf("Expected decision to be schedulable after reset")
+	}
+}
+
+func TestBotDecisionSchedulerAwaitsAcceptance(t *testing.T) {
+	scheduler := NewBotDecisionScheduler()
+	sent := func() bool { return true }
+
+	scheduler.Schedule("bot1", "bot1:action:1:2", 0, sent)
+	scheduler.RunDue()
+	if !scheduler.IsScheduled("bot1:action:1:2") {
+		t.Errorf("Expected a sent decision not to be scheduled twice before it is processed")
+	}
+	scheduler.Settle()
+	if !scheduler.Schedule("bot1", "bot1:action:1:2", 0, sent) {
+		t.Errorf("Expected a refused decision to be scheduled again")
+	}
+	scheduler.RunDue()
+	scheduler.Accept("bot1")
+	scheduler.Settle()
+	if scheduler.Schedule("bot1", "bot1:action:1:2", 0, sent) {
+		t.Errorf("Expected an accepted decision to be done")
+	}
+}
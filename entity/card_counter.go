@@ -0,0 +1,110 @@
+package entity
+
+import (
+	"math"
+	"sync"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+// CardsPerDeck is the number of cards in a single deck of the shoe.
+const CardsPerDeck = 52
+
+var (
+	persistentShoeMu sync.RWMutex
+	persistentShoe   bool
+)
+
+// SetPersistentShoe deals one shoe over several rounds until the cut card is reached,
+// so counting bots have a count to play on. Off by default, every round gets a new shoe.
+func SetPersistentShoe(on bool) {
+	persistentShoeMu.Lock()
+	defer persistentShoeMu.Unlock()
+	persistentShoe = on
+}
+
+// PersistentShoe reports whether a shoe is dealt over several rounds
+func PersistentShoe() bool {
+	persistentShoeMu.RLock()
+	defer persistentShoeMu.RUnlock()
+	return persistentShoe
+}
+
+// HiLoCounter keeps a Hi-Lo running count over the cards seen from a shoe
+type HiLoCounter struct {
+	runningCount   int
+	cardsSeen      int
+	cardsRemaining int
+}
+
+// NewHiLoCounter creates a counter for a freshly shuffled shoe of shoeSize cards
+func NewHiLoCounter(shoeSize int) *HiLoCounter {
+	return &HiLoCounter{
+		cardsRemaining: shoeSize,
+	}
+}
+
+// HiLoValue returns the Hi-Lo tag of a card: 2-6 count +1, 7-9 count 0, tens and aces count -1
+func HiLoValue(card *pb.Card) int {
+	if card == nil || card.Rank == pb.CardRank_RANK_UNSPECIFIED {
+		return 0
+	}
+	switch card.Rank {
+	case pb.CardRank_RANK_2, pb.CardRank_RANK_3, pb.CardRank_RANK_4, pb.CardRank_RANK_5, pb.CardRank_RANK_6:
+		return 1
+	case pb.CardRank_RANK_7, pb.CardRank_RANK_8, pb.CardRank_RANK_9:
+		return 0
+	default:
+		return -1
+	}
+}
+
+// Observe adds dealt cards to the running count
+func (c *HiLoCounter) Observe(cards ...*pb.Card) {
+	for _, card := range cards {
+		if card == nil || card.Rank == pb.CardRank_RANK_UNSPECIFIED {
+			// face down card, nothing to count yet
+			continue
+		}
+		c.runningCount += HiLoValue(card)
+		c.cardsSeen++
+		if c.cardsRemaining > 0 {
+			c.cardsRemaining--
+		}
+	}
+}
+
+// SetCardsRemaining syncs the number of undealt cards with the real shoe
+func (c *HiLoCounter) SetCardsRemaining(n int) {
+	if n < 0 {
+		n = 0
+	}
+	c.cardsRemaining = n
+}
+
+// Reset clears the count after the shoe has been reshuffled
+func (c *HiLoCounter) Reset(shoeSize int) {
+	c.runningCount = 0
+	c.cardsSeen = 0
+	c.cardsRemaining = shoeSize
+}
+
+// RunningCount returns the current Hi-Lo running count
+func (c *HiLoCounter) RunningCount() int {
+	return c.runningCount
+}
+
+// CardsSeen returns how many cards have been counted since the last shuffle
+func (c *HiLoCounter) CardsSeen() int {
+	return c.cardsSeen
+}
+
+// DecksRemaining estimates the decks left in the shoe, never less than half a deck
+func (c *HiLoCounter) DecksRemaining() float64 {
+	return math.Max(float64(c.cardsRemaining)/CardsPerDeck, 0.5)
+}
+
+// TrueCount returns the running count divided by the decks remaining
+func (c *HiLoCounter) TrueCount() float64 {
+	return float64(c.runningCount) / c.DecksRemaining()
+}
@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"testing"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func TestHiLoCounter(t *testing.T) {
+	counter := NewHiLoCounter(2 * CardsPerDeck)
+	counter.Observe(
+		&pb.Card{Rank: pb.CardRank_RANK_2},
+		&pb.Card{Rank: pb.CardRank_RANK_5},
+		&pb.Card{Rank: pb.CardRank_RANK_6},
+		&pb.Card{Rank: pb.CardRank_RANK_8},
+		&pb.Card{Rank: pb.CardRank_RANK_K},
+		// face down card is not counted
+		&pb.Card{Rank: pb.CardRank_RANK_UNSPECIFIED},
+	)
+	if counter.RunningCount() != 2 {
+		t.Errorf("Expected running count 2, got %d", counter.RunningCount())
+	}
+	if counter.CardsSeen() != 5 {
+		t.Errorf("Expected 5 cards seen, got %d", counter.CardsSeen())
+	}
+
+	counter.SetCardsRemaining(CardsPerDeck / 2)
+	if tc := counter.TrueCount(); tc != 4 {
+		t.Errorf("Expected true count 4 with half a deck left, got %f", tc)
+	}
+
+	counter.Reset(MaxCard)
+	if counter.RunningCount() != 0 || counter.TrueCount() != 0 {
+		t.Errorf("Expected count to be cleared after reset")
+	}
+}
+
+func TestCardCountingBotLogic(t *testing.T) {
+	botLogic := NewCardCountingBotLogic()
+	botLogic.SetRiskLevel("conservative")
+	botLogic.bettingStrategy.BetAmountStrategy.ProgressiveBetting = false
+	botLogic.SetBalance(100000)
+
+	flatBet := botLogic.DecideBetAmount()
+
+	// a shoe rich in tens and aces
+	lowCards := make([]*pb.Card, 0)
+	for i := 0; i < 12; i++ {
+		lowCards = append(lowCards, &pb.Card{Rank: pb.CardRank_RANK_4})
+	}
+	botLogic.ObserveCards(lowCards...)
+	botLogic.SetShoeRemaining(2 * CardsPerDeck)
+	if tc := botLogic.TrueCount(); tc != 6 {
+		t.Fatalf("Expected true count 6, got %f", tc)
+	}
+
+	if rampedBet := botLogic.DecideBetAmount(); rampedBet <= flatBet {
+		t.Errorf("Expected bet to grow with the count, flat %d, ramped %d", flatBet, rampedBet)
+	}
+
+	dealerAce := &pb.Card{Rank: pb.CardRank_RANK_A, Suit: pb.CardSuit_SUIT_SPADES}
+	hand := &pb.BlackjackHand{
+		Cards: []*pb.Card{
+			{Rank: pb.CardRank_RANK_9, Suit: pb.CardSuit_SUIT_SPADES},
+			{Rank: pb.CardRank_RANK_7, Suit: pb.CardSuit_SUIT_HEARTS},
+		},
+		Point: 16,
+	}
+	if !botLogic.ShouldTakeInsurance(hand, dealerAce) {
+		t.Errorf("Expected counting bot to take insurance at true count >= %d", InsuranceTrueCountIndex)
+	}
+
+	// 16 vs 10 stands from true count 0
+	dealerTen := &pb.Card{Rank: pb.CardRank_RANK_10, Suit: pb.CardSuit_SUIT_CLUBS}
+	action := botLogic.DecideGameAction(hand, dealerTen, []pb.BlackjackActionCode{
+		pb.BlackjackActionCode_BLACKJACK_ACTION_HIT,
+		pb.BlackjackActionCode_BLACKJACK_ACTION_STAY,
+	})
+	if action != pb.BlackjackActionCode_BLACKJACK_ACTION_STAY {
+		t.Errorf("Expected 16 vs 10 to stand at a positive count, got %v", action)
+	}
+
+	// negative count, no insurance
+	botLogic.ResetCount(MaxCard)
+	highCards := make([]*pb.Card, 0)
+	for i := 0; i < 20; i++ {
+		highCards = append(highCards, &pb.Card{Rank: pb.CardRank_RANK_K})
+	}
+	botLogic.ObserveCards(highCards...)
+	if botLogic.ShouldTakeInsurance(hand, dealerAce) {
+		t.Errorf("Expected counting bot to decline insurance at a negative count")
+	}
+}
+
+func TestPersistentShoe(t *testing.T) {
+	if PersistentShoe() {
+		t.Fatalf("Expected a new shoe every round by default")
+	}
+	SetPersistentShoe(true)
+	defer SetPersistentShoe(false)
+	state := NewMatchState(&pb.Match{MarkUnit: 100})
+	if !state.KeepShoe() {
+		t.Errorf("Expected the shoe to be kept once enabled")
+	}
+}
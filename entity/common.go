@@ -43,8 +43,18 @@ type WalletAction string
 
 const (
 	WalletActionWinGameJackpot WalletAction = "win_game_jackpot"
+	WalletActionBotTopUp       WalletAction = "bot_top_up"
+	WalletActionBotTrim        WalletAction = "bot_trim"
+	WalletActionPropBet        WalletAction = "prop_bet"
+	WalletActionPropBetWin     WalletAction = "prop_bet_win"
+	WalletActionPropBetRefund  WalletAction = "prop_bet_refund"
+	WalletActionRoundRefund    WalletAction = "round_refund"
 )
 
+// WalletLedgerHouseBot is the ledger category of house funded bot wallet adjustments,
+// kept apart from real player flow
+const WalletLedgerHouseBot = "house_bot"
+
 func InterfaceToString(inf interface{}) string {
 	if inf == nil {
 		return ""
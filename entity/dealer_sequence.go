@@ -0,0 +1,100 @@
+package entity
+
+import (
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+// Steps of the dealer play
+const (
+	DealerStepReveal = "reveal"
+	DealerStepDraw   = "draw"
+	DealerStepTotal  = "total"
+)
+
+// DealerStep is a step of the dealer play, Seq starts at 1 and Total is the number of steps,
+// so a client joining mid-sequence knows what it missed and what is still coming
+type DealerStep struct {
+	Seq   int    `json:"seq"`
+	Total int    `json:"total"`
+	Step  string `json:"step"`
+	// Card shown by the step, none for the total
+	Card *pb.Card `json:"card,omitempty"`
+	// Dealer cards face up after the step and their point
+	Cards []*pb.Card `json:"cards"`
+	Point int32      `json:"point"`
+	// How the dealer hand ended, only set on the total
+	Result string `json:"result,omitempty"`
+}
+
+// DealerSequence is the dealer play of the round, shown a step at a time
+type DealerSequence struct {
+	Steps []*DealerStep `json:"steps"`
+	// Steps sent to clients so far
+	Shown int `json:"shown"`
+}
+
+// BuildDealerSequence splits the dealer play into the hole card reveal, each draw and the
+// final total, to be called once the dealer drew every card
+func (s *MatchState) BuildDealerSequence() *DealerSequence {
+	cards := s.dealerHand.first
+	sequence := &DealerSequence{Steps: make([]*DealerStep, 0, len(cards))}
+	if len(cards) < 2 {
+		s.dealerSequence = sequence
+		return sequence
+	}
+	for n := 2; n <= len(cards); n++ {
+		step := DealerStepDraw
+		if n == 2 {
+			step = DealerStepReveal
+		}
+		sequence.Steps = append(sequence.Steps, &DealerStep{
+			Step:  step,
+			Card:  cards[n-1],
+			Cards: cards[:n],
+			Point: NewHand("", cards[:n], nil).ToPb().First.Point,
+		})
+	}
+	final := s.GetDealerHand().First
+	sequence.Steps = append(sequence.Steps, &DealerStep{
+		Step:   DealerStepTotal,
+		Cards:  cards,
+		Point:  final.Point,
+		Result: dealerResult(final),
+	})
+	for i, step := range sequence.Steps {
+		step.Seq = i + 1
+		step.Total = len(sequence.Steps)
+	}
+	s.dealerSequence = sequence
+	return sequence
+}
+
+// ShowDealerStep records that the step seq was sent to clients
+func (s *MatchState) ShowDealerStep(seq int) {
+	if s.dealerSequence != nil && seq > s.dealerSequence.Shown && seq <= len(s.dealerSequence.Steps) {
+		s.dealerSequence.Shown = seq
+	}
+}
+
+// GetDealerSequence returns the dealer play of the round, nil before the dealer plays
+func (s *MatchState) GetDealerSequence() *DealerSequence {
+	return s.dealerSequence
+}
+
+// ResetDealerSequence forgets the dealer play once the round is over
+func (s *MatchState) ResetDealerSequence() {
+	s.dealerSequence = nil
+}
+
+// shownDealerCards returns the dealer cards face up so far while the dealer plays
+func (s *MatchState) shownDealerCards() []*pb.Card {
+	sequence := s.dealerSequence
+	if sequence == nil || sequence.Shown == 0 {
+		cards := s.dealerHand.first
+		if len(cards) > 1 {
+			return []*pb.Card{cards[0], {Rank: pb.CardRank_RANK_UNSPECIFIED, Suit: pb.CardSuit_SUIT_UNSPECIFIED}}
+		}
+		return cards
+	}
+	return sequence.Steps[sequence.Shown-1].Cards
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"testing"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func TestDealerSequence(t *testing.T) {
+	state := NewMatchState(&pb.Match{MatchId: "m1", MarkUnit: 100})
+	state.SetGameState(pb.GameState_GAME_STATE_REWARD)
+	state.AddCards([]*pb.Card{
+		{Rank: pb.CardRank_RANK_6, Suit: pb.CardSuit_SUIT_SPADES},
+		{Rank: pb.CardRank_RANK_5, Suit: pb.CardSuit_SUIT_HEARTS},
+	}, "", pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
+	for _, rank := range []pb.CardRank{pb.CardRank_RANK_4, pb.CardRank_RANK_K} {
+		state.AddCards([]*pb.Card{{Rank: rank, Suit: pb.CardSuit_SUIT_CLUBS}}, "", pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
+	}
+
+	sequence := state.BuildDealerSequence()
+	if len(sequence.Steps) != 4 {
+		t.Fatalf("Expected reveal, 2 draws and total, got %d steps", len(sequence.Steps))
+	}
+	steps := []string{DealerStepReveal, DealerStepDraw, DealerStepDraw, DealerStepTotal}
+	for i, step := range sequence.Steps {
+		if step.Step != steps[i] || step.Seq != i+1 || step.Total != 4 {
+			t.Errorf("Unexpected step %d: %+v", i, step)
+		}
+	}
+	if sequence.Steps[0].Point != 11 || sequence.Steps[1].Point != 15 || len(sequence.Steps[1].Cards) != 3 {
+		t.Errorf("Unexpected partial points %+v %+v", sequence.Steps[0], sequence.Steps[1])
+	}
+	if total := sequence.Steps[3]; total.Card != nil || total.Point != 25 || total.Result != DealerResultBust {
+		t.Errorf("Expected a busted total, got %+v", total)
+	}
+
+	// a client joining before any step still sees the hole card hidden
+	snapshot := state.TableSnapshot("p1")
+	if cards := snapshot.DealerHand.First.Cards; len(cards) != 2 || cards[1].Rank != pb.CardRank_RANK_UNSPECIFIED {
+		t.Errorf("Expected the hole card hidden, got %+v", cards)
+	}
+	// mid-sequence it catches up on the steps sent so far
+	state.ShowDealerStep(2)
+	snapshot = state.TableSnapshot("p1")
+	if len(snapshot.DealerSequence.Steps) != 2 || len(snapshot.DealerHand.First.Cards) != 3 || snapshot.Finish != nil {
+		t.Errorf("Expected 2 steps and 3 cards without the result, got %+v", snapshot.DealerSequence)
+	}
+	state.ShowDealerStep(1)
+	state.ShowDealerStep(9)
+	if state.GetDealerSequence().Shown != 2 {
+		t.Errorf("Expected shown steps to only move forward, got %d", state.GetDealerSequence().Shown)
+	}
+	state.ShowDealerStep(4)
+	if snapshot = state.TableSnapshot("p1"); len(snapshot.DealerHand.First.Cards) != 4 {
+		t.Errorf("Expected the whole dealer hand, got %+v", snapshot.DealerHand)
+	}
+
+	state.ResetDealerSequence()
+	if state.GetDealerSequence() != nil {
+		t.Error("Expected the sequence to be reset")
+	}
+}
@@ -9,6 +9,9 @@ import (
 
 const MaxCard = 312
 
+// ShoePenetration is the fraction of the shoe dealt before it is reshuffled
+const ShoePenetration = 0.75
+
 type Deck struct {
 	ListCard *pb.ListCard
 	Dealt    int
@@ -87,3 +90,13 @@ func (d *Deck) Deal(n int) (*pb.ListCard, error) {
 	}
 	return &cards, nil
 }
+
+// Remaining returns the number of cards not dealt yet
+func (d *Deck) Remaining() int {
+	return len(d.ListCard.Cards) - d.Dealt
+}
+
+// NeedReshuffle reports whether the cut card has been reached
+func (d *Deck) NeedReshuffle() bool {
+	return d.Dealt >= int(float64(len(d.ListCard.Cards))*ShoePenetration)
+}
@@ -0,0 +1,191 @@
+package entity
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+// KickReasonDisconnected is sent when a held seat is given up
+const KickReasonDisconnected = "disconnected"
+
+// How the hands of a disconnected player are played while the seat is held
+const (
+	AutoPlayStand         = "stand"
+	AutoPlayBasicStrategy = "basic_strategy"
+)
+
+const (
+	DefaultDisconnectGrace = 60 * time.Second
+	DefaultAutoPlay        = AutoPlayBasicStrategy
+	// AutoPlayDelay is how long a disconnected player's turn waits before being played
+	AutoPlayDelay = 1500 * time.Millisecond
+)
+
+type DisconnectedUpdate struct {
+	UserId string `json:"user_id"`
+	// Disconnected is false once the player is back
+	Disconnected bool `json:"disconnected"`
+	// Unix time the seat is given up at
+	HeldUntil int64 `json:"held_until,omitempty"`
+}
+
+var (
+	disconnectPolicyMu sync.RWMutex
+	disconnectGrace    = DefaultDisconnectGrace
+	autoPlayPolicy     = DefaultAutoPlay
+)
+
+// SetDisconnectPolicy sets how long the seat of a disconnected player is held
+// and how its hands are played meanwhile
+func SetDisconnectPolicy(grace time.Duration, autoPlay string) error {
+	if grace < 0 {
+		return fmt.Errorf("negative disconnect grace %v", grace)
+	}
+	if autoPlay != AutoPlayStand && autoPlay != AutoPlayBasicStrategy {
+		return fmt.Errorf("unknown auto play policy %q", autoPlay)
+	}
+	disconnectPolicyMu.Lock()
+	defer disconnectPolicyMu.Unlock()
+	disconnectGrace = grace
+	autoPlayPolicy = autoPlay
+	return nil
+}
+
+func getDisconnectPolicy() (time.Duration, string) {
+	disconnectPolicyMu.RLock()
+	defer disconnectPolicyMu.RUnlock()
+	return disconnectGrace, autoPlayPolicy
+}
+
+// MarkDisconnected holds the seat of a player dropping mid-round
+func (s *MatchState) MarkDisconnected(userId string) *DisconnectedUpdate {
+	if _, found := s.disconnected[userId]; !found {
+		s.disconnected[userId] = time.Now()
+	}
+	grace, _ := getDisconnectPolicy()
+	return &DisconnectedUpdate{
+		UserId:       userId,
+		Disconnected: true,
+		HeldUntil:    s.disconnected[userId].Add(grace).Unix(),
+	}
+}
+
+func (s *MatchState) IsDisconnected(userId string) bool {
+	_, found := s.disconnected[userId]
+	return found
+}
+
+// Reconnect gives control back to a player whose seat was held,
+// a pending auto-play decision is dropped
+func (s *MatchState) Reconnect(userId string) bool {
+	if _, found := s.disconnected[userId]; !found {
+		return false
+	}
+	delete(s.disconnected, userId)
+	s.botScheduler.Cancel(userId)
+	return true
+}
+
+// HoldDisconnectedSeats keeps the seats of disconnected players out of the pending leaves
+// during the grace period, and queues the leave of those past it
+func (s *MatchState) HoldDisconnectedSeats(now time.Time) {
+	grace, _ := getDisconnectPolicy()
+	for userId, since := range s.disconnected {
+		presence := s.GetPresence(userId)
+		if presence == nil {
+			delete(s.disconnected, userId)
+			continue
+		}
+		if now.Sub(since) < grace {
+			s.RemoveLeavePresence(userId)
+			continue
+		}
+		delete(s.disconnected, userId)
+		s.SetKickReason(userId, KickReasonDisconnected)
+		s.AddLeavePresence(presence)
+	}
+}
+
+// ScheduleAutoPlay queues the decision on the current hand of a disconnected player
+func (s *MatchState) ScheduleAutoPlay(logger runtime.Logger, userId string) bool {
+	if !s.IsDisconnected(userId) {
+		return false
+	}
+	handN0 := s.currentHand[userId]
+	numCards := 0
+	if playerHand := s.GetPlayerPartOfHand(userId, handN0); playerHand != nil {
+		numCards = len(playerHand.Cards)
+	}
+	key := fmt.Sprintf("%s:autoplay:%d:%d", userId, handN0, numCards)
+	if !s.IsInTurn(userId) || s.botScheduler.IsScheduled(key) || len(s.GetLegalActionsByUserId(userId)) == 0 {
+		return false
+	}
+	remain := time.Duration(s.GetRemainCountDown() * float64(time.Second))
+	return s.botScheduler.Schedule(userId, key, ClampThinkTime(AutoPlayDelay, remain), func() bool {
+		if !s.IsAllowAction() || !s.IsInTurn(userId) || !s.IsDisconnected(userId) {
+			return false
+		}
+		legalActions := s.GetLegalActionsByUserId(userId)
+		if len(legalActions) == 0 {
+			return false
+		}
+		presence := s.GetPresence(userId)
+		if presence == nil {
+			return false
+		}
+		action := s.AutoPlayAction(userId, legalActions)
+		logger.WithField("user_id", userId).WithField("action", action.String()).Debug("auto play for disconnected user")
+		buf, _ := marshaler.Marshal(&pb.BlackjackAction{UserId: userId, Code: action})
+		s.AddMessages(&autoPlayData{
+			Presence:    presence,
+			opCode:      int64(pb.OpCodeRequest_OPCODE_REQUEST_DECLARE_CARDS),
+			data:        buf,
+			receiveTime: time.Now().Unix(),
+		})
+		return true
+	})
+}
+
+// AutoPlayAction decides the action on the current hand of a disconnected player,
+// auto-play never doubles or splits on the player's behalf
+func (s *MatchState) AutoPlayAction(userId string, legalActions []pb.BlackjackActionCode) pb.BlackjackActionCode {
+	_, policy := getDisconnectPolicy()
+	return s.playFor(userId, legalActions, policy)
+}
+
+// playFor picks the action played for userId, stand or a hit when basic strategy says so.
+// Doubles and splits are never taken since they put more chips at stake.
+func (s *MatchState) playFor(userId string, legalActions []pb.BlackjackActionCode, policy string) pb.BlackjackActionCode {
+	stay := pb.BlackjackActionCode_BLACKJACK_ACTION_STAY
+	if policy != AutoPlayBasicStrategy {
+		return stay
+	}
+	playerHand := s.GetPlayerPartOfHand(userId, s.currentHand[userId])
+	upCard := s.dealerUpCard()
+	if playerHand == nil || upCard == nil {
+		return stay
+	}
+	logic := &BlackjackBotLogic{}
+	action := logic.basicStrategy(playerHand, upCard, legalActions)
+	if action != pb.BlackjackActionCode_BLACKJACK_ACTION_HIT || !logic.containsAction(legalActions, action) {
+		return stay
+	}
+	return action
+}
+
+// autoPlayData is an action sent on behalf of a disconnected player
+type autoPlayData struct {
+	runtime.Presence
+	opCode      int64
+	data        []byte
+	receiveTime int64
+}
+
+func (d *autoPlayData) GetOpCode() int64      { return d.opCode }
+func (d *autoPlayData) GetData() []byte       { return d.data }
+func (d *autoPlayData) GetReliable() bool     { return true }
+func (d *autoPlayData) GetReceiveTime() int64 { return d.receiveTime }
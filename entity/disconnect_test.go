@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func TestHoldDisconnectedSeats(t *testing.T) {
+	if err := SetDisconnectPolicy(time.Minute, AutoPlayStand); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer SetDisconnectPolicy(DefaultDisconnectGrace, DefaultAutoPlay)
+	if err := SetDisconnectPolicy(time.Minute, "random"); err == nil {
+		t.Errorf("Expected an unknown policy to be refused")
+	}
+
+	state := newTestTable("p1")
+	state.AddLeavePresence(&FakePrecense{UserId: "p1"})
+	if update := state.MarkDisconnected("p1"); !update.Disconnected || update.HeldUntil == 0 {
+		t.Errorf("Unexpected update %+v", update)
+	}
+
+	state.HoldDisconnectedSeats(time.Now())
+	if len(state.GetLeavePresences()) != 0 || state.GetSeat("p1") != 1 {
+		t.Errorf("Expected the seat to be held during the grace period")
+	}
+
+	state.HoldDisconnectedSeats(time.Now().Add(2 * time.Minute))
+	if leaves := state.GetLeavePresences(); len(leaves) != 1 || state.IsDisconnected("p1") {
+		t.Errorf("Expected the seat to be given up after the grace period")
+	}
+	if state.PopKickReason("p1") != KickReasonDisconnected {
+		t.Errorf("Expected the disconnected kick reason")
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	state := newTestTable("p1")
+	state.MarkDisconnected("p1")
+	if !state.Reconnect("p1") || state.IsDisconnected("p1") {
+		t.Errorf("Expected control back to the player")
+	}
+	if state.Reconnect("p1") || state.ScheduleAutoPlay(nil, "p1") {
+		t.Errorf("Expected nothing to do for a connected player")
+	}
+}
+
+func TestAutoPlayAction(t *testing.T) {
+	defer SetDisconnectPolicy(DefaultDisconnectGrace, DefaultAutoPlay)
+	state := newTestTable()
+	state.AddCards([]*pb.Card{
+		{Rank: pb.CardRank_RANK_10, Suit: pb.CardSuit_SUIT_SPADES},
+		{Rank: pb.CardRank_RANK_7, Suit: pb.CardSuit_SUIT_HEARTS},
+	}, "", pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
+	state.AddCards([]*pb.Card{
+		{Rank: pb.CardRank_RANK_5, Suit: pb.CardSuit_SUIT_CLUBS},
+		{Rank: pb.CardRank_RANK_6, Suit: pb.CardSuit_SUIT_DIAMONDS},
+	}, "p1", pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
+	legal := []pb.BlackjackActionCode{
+		pb.BlackjackActionCode_BLACKJACK_ACTION_HIT,
+		pb.BlackjackActionCode_BLACKJACK_ACTION_DOUBLE,
+		pb.BlackjackActionCode_BLACKJACK_ACTION_STAY,
+	}
+
+	SetDisconnectPolicy(time.Minute, AutoPlayBasicStrategy)
+	if action := state.AutoPlayAction("p1", legal); action != pb.BlackjackActionCode_BLACKJACK_ACTION_HIT {
+		t.Errorf("Expected basic strategy to hit 11 without doubling, got %v", action)
+	}
+	SetDisconnectPolicy(time.Minute, AutoPlayStand)
+	if action := state.AutoPlayAction("p1", legal); action != pb.BlackjackActionCode_BLACKJACK_ACTION_STAY {
+		t.Errorf("Expected the stand policy to stay, got %v", action)
+	}
+}
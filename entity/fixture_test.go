@@ -0,0 +1,13 @@
+package entity
+
+import pb "github.com/nk-nigeria/cgp-common/proto"
+
+// newTestTable returns a table with the given users seated from first base
+func newTestTable(userIds ...string) *MatchState {
+	state := NewMatchState(&pb.Match{MarkUnit: 100})
+	for _, id := range userIds {
+		state.Presences.Put(id, &FakePrecense{UserId: id})
+		state.assignSeat(id)
+	}
+	return &state
+}
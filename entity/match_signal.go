@@ -0,0 +1,244 @@
+package entity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Commands operators signal a match with
+const (
+	// SignalPause holds the table once the current round is over
+	SignalPause = "pause"
+	// SignalResume lets a paused table deal again
+	SignalResume = "resume"
+	// SignalClose finishes the current round then terminates the match
+	SignalClose = "close"
+	// SignalRefund gives every stake of the current round back without settling it
+	SignalRefund = "refund"
+	// SignalInspect replies with a dump of the match state
+	SignalInspect = "inspect"
+	// SignalKick removes UserId from the table, a seated bot or a human
+	SignalKick = "kick"
+	// SignalAddBots seats Count more bots
+	SignalAddBots = "add_bots"
+	// SignalRemoveBot takes the bot UserId off the table
+	SignalRemoveBot = "remove_bot"
+	// SignalSetLimits changes the main bet limits of the table
+	SignalSetLimits = "set_limits"
+)
+
+// Errors replied to a signal
+var (
+	ErrSignalBadPayload   = errors.New("bad_payload")
+	ErrSignalUnauthorized = errors.New("unauthorized")
+	ErrSignalExpired      = errors.New("expired")
+	ErrSignalReplayed     = errors.New("replayed")
+	ErrSignalUnknown      = errors.New("unknown_command")
+	ErrSignalClosing      = errors.New("closing")
+	ErrSignalNoRound      = errors.New("no_round")
+	ErrSignalSettled      = errors.New("round_settled")
+	ErrSignalBadArgument  = errors.New("bad_argument")
+	ErrSignalNotFound     = errors.New("not_found")
+	ErrSignalNoBot        = errors.New("no_bot")
+	ErrSignalInRound      = errors.New("round_in_play")
+)
+
+// MatchSignalMaxAge is how far IssuedAt may be from the server clock, older signals
+// are refused and a match only remembers the nonces it saw within that window
+var MatchSignalMaxAge = 5 * time.Minute
+
+// MatchSignal is the payload of a match signal, signed by the operator sending it
+type MatchSignal struct {
+	Command    string `json:"command"`
+	OperatorId string `json:"operator_id"`
+	Reason     string `json:"reason,omitempty"`
+	// Player of kick and bot of remove_bot
+	UserId string `json:"user_id,omitempty"`
+	// Bots seated by add_bots
+	Count int `json:"count,omitempty"`
+	// Dumps the dealer hole card in an inspect
+	HoleCard bool `json:"hole_card,omitempty"`
+	// Limits of set_limits, 0 removes a limit
+	MinBet int64 `json:"min_bet,omitempty"`
+	MaxBet int64 `json:"max_bet,omitempty"`
+	// Unix time in seconds the signal was signed at
+	IssuedAt int64 `json:"issued_at"`
+	// Unique per signal, a match applies a nonce once
+	Nonce string `json:"nonce"`
+	// Hex HMAC-SHA256 of the match id and the fields above with the signal secret
+	Signature string `json:"signature"`
+}
+
+// MatchSignalReply is returned to the operator, Error is empty when the command was applied
+type MatchSignalReply struct {
+	Command string `json:"command"`
+	Ok      bool   `json:"ok"`
+	Error   string `json:"error,omitempty"`
+	// Table status after the command
+	Paused  bool `json:"paused"`
+	Closing bool `json:"closing"`
+	// Chips given back by a refund, main and prop bets
+	Refunded int64 `json:"refunded,omitempty"`
+	// Match state of an inspect
+	State *MatchStateDump `json:"state,omitempty"`
+	// Limits of the table after set_limits
+	Limits *TableLimits `json:"limits,omitempty"`
+	// The signal could not be written to the audit table
+	AuditError bool `json:"audit_error,omitempty"`
+}
+
+// TableStatus is broadcast when an operator pauses, resumes or closes the table
+type TableStatus struct {
+	Paused  bool   `json:"paused"`
+	Closing bool   `json:"closing"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+var (
+	signalSecretMu sync.RWMutex
+	// no secret refuses every signal
+	signalSecret string
+)
+
+// SetMatchSignalSecret sets the secret match signals are signed with
+func SetMatchSignalSecret(secret string) {
+	signalSecretMu.Lock()
+	defer signalSecretMu.Unlock()
+	signalSecret = secret
+}
+
+// MatchSignalsEnabled reports whether a signal secret is set, signals are refused otherwise
+func MatchSignalsEnabled() bool {
+	return matchSignalSecret() != ""
+}
+
+func matchSignalSecret() string {
+	signalSecretMu.RLock()
+	defer signalSecretMu.RUnlock()
+	return signalSecret
+}
+
+// ParseMatchSignal decodes the data of a match signal
+func ParseMatchSignal(data string) (*MatchSignal, error) {
+	sig := &MatchSignal{}
+	if err := json.Unmarshal([]byte(data), sig); err != nil {
+		return nil, ErrSignalBadPayload
+	}
+	if sig.Command == "" || sig.OperatorId == "" || sig.Nonce == "" {
+		return nil, ErrSignalBadPayload
+	}
+	return sig, nil
+}
+
+func (sig *MatchSignal) mac(secret, matchId string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(strings.Join([]string{
+		matchId, sig.Command, sig.OperatorId, sig.Reason,
+		sig.UserId, strconv.Itoa(sig.Count), strconv.FormatBool(sig.HoleCard),
+		strconv.FormatInt(sig.MinBet, 10), strconv.FormatInt(sig.MaxBet, 10),
+		strconv.FormatInt(sig.IssuedAt, 10), sig.Nonce,
+	}, "\n")))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Sign signs the signal for matchId with the signal secret, it is left unsigned without one
+func (sig *MatchSignal) Sign(matchId string) {
+	if secret := matchSignalSecret(); secret != "" {
+		sig.Signature = sig.mac(secret, matchId)
+	}
+}
+
+// Verify checks the signal was signed for matchId with the signal secret not long before now
+func (sig *MatchSignal) Verify(matchId string, now time.Time) error {
+	secret := matchSignalSecret()
+	if secret == "" || !hmac.Equal([]byte(sig.Signature), []byte(sig.mac(secret, matchId))) {
+		return ErrSignalUnauthorized
+	}
+	age := now.Sub(time.Unix(sig.IssuedAt, 0))
+	if age > MatchSignalMaxAge || age < -MatchSignalMaxAge {
+		return ErrSignalExpired
+	}
+	return nil
+}
+
+// AcceptSignalNonce records the nonce of a verified signal, false when the match already
+// saw it. Nonces are forgotten once their signal is too old to verify.
+func (s *MatchState) AcceptSignalNonce(sig *MatchSignal, now time.Time) bool {
+	for nonce, issuedAt := range s.signalNonces {
+		if now.Sub(time.Unix(issuedAt, 0)) > MatchSignalMaxAge {
+			delete(s.signalNonces, nonce)
+		}
+	}
+	if _, seen := s.signalNonces[sig.Nonce]; seen {
+		return false
+	}
+	s.signalNonces[sig.Nonce] = sig.IssuedAt
+	return true
+}
+
+func (s *MatchState) IsPaused() bool  { return s.paused }
+func (s *MatchState) IsClosing() bool { return s.closing }
+
+// SetPaused holds the table before the next round, the reason is shown to clients
+func (s *MatchState) SetPaused(paused bool, reason string) {
+	s.paused = paused
+	s.statusReason = reason
+}
+
+// SetClosing terminates the match once the current round is over
+func (s *MatchState) SetClosing(reason string) {
+	s.closing = true
+	s.statusReason = reason
+}
+
+// GetTableStatus returns the status set by operators, nil while the table runs normally
+func (s *MatchState) GetTableStatus() *TableStatus {
+	if !s.paused && !s.closing {
+		return nil
+	}
+	return &TableStatus{Paused: s.paused, Closing: s.closing, Reason: s.statusReason}
+}
+
+// RefundRoundBets clears the bets of the round and returns the chips taken from each
+// wallet for them, only what was charged is given back
+func (s *MatchState) RefundRoundBets() map[string]int64 {
+	refunds := make(map[string]int64)
+	for userId, chips := range s.stakes {
+		if chips > 0 {
+			refunds[userId] = chips
+		}
+	}
+	s.InitUserBet()
+	s.roundRefunded = true
+	return refunds
+}
+
+// DropRoundBet takes the bet of userId off a round not dealt yet and returns the chips
+// charged for it
+func (s *MatchState) DropRoundBet(userId string) int64 {
+	chips := s.stakes[userId]
+	delete(s.stakes, userId)
+	delete(s.userBets, userId)
+	return chips
+}
+
+// BanUser keeps userId, kicked by an operator, out of the match until it ends
+func (s *MatchState) BanUser(userId string) {
+	s.banned[userId] = true
+}
+
+func (s *MatchState) IsBanned(userId string) bool {
+	return s.banned[userId]
+}
+
+// IsRoundRefunded reports whether the stakes of the round were given back, so it is not settled
+func (s *MatchState) IsRoundRefunded() bool {
+	return s.roundRefunded
+}
@@ -0,0 +1,132 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func TestMatchSignalVerify(t *testing.T) {
+	defer SetMatchSignalSecret("")
+	now := time.Unix(1700000000, 0)
+	signed := func(matchId string) *MatchSignal {
+		sig := &MatchSignal{Command: SignalPause, OperatorId: "ops-1", Reason: "maintenance", IssuedAt: now.Unix(), Nonce: "n1"}
+		sig.Sign(matchId)
+		return sig
+	}
+
+	SetMatchSignalSecret("")
+	if err := signed("m1").Verify("m1", now); err != ErrSignalUnauthorized {
+		t.Fatalf("no secret: got %v, want unauthorized", err)
+	}
+
+	SetMatchSignalSecret("s3cret")
+	if err := signed("m1").Verify("m1", now); err != nil {
+		t.Fatalf("signed signal refused: %v", err)
+	}
+	if err := signed("m2").Verify("m1", now); err != ErrSignalUnauthorized {
+		t.Errorf("signal of another match: got %v, want unauthorized", err)
+	}
+	tampered := signed("m1")
+	tampered.Command = SignalClose
+	if err := tampered.Verify("m1", now); err != ErrSignalUnauthorized {
+		t.Errorf("tampered command: got %v, want unauthorized", err)
+	}
+	if err := signed("m1").Verify("m1", now.Add(MatchSignalMaxAge+time.Second)); err != ErrSignalExpired {
+		t.Errorf("old signal: got %v, want expired", err)
+	}
+	renonced := signed("m1")
+	renonced.Nonce = "n2"
+	if err := renonced.Verify("m1", now); err != ErrSignalUnauthorized {
+		t.Errorf("changed nonce: got %v, want unauthorized", err)
+	}
+}
+
+func TestAcceptSignalNonce(t *testing.T) {
+	state := newTestTable()
+	now := time.Unix(1700000000, 0)
+	sig := &MatchSignal{Nonce: "n1", IssuedAt: now.Unix()}
+	if !state.AcceptSignalNonce(sig, now) {
+		t.Fatalf("first use of a nonce refused")
+	}
+	if state.AcceptSignalNonce(sig, now.Add(time.Minute)) {
+		t.Errorf("replayed nonce accepted")
+	}
+	state.AcceptSignalNonce(&MatchSignal{Nonce: "n2", IssuedAt: now.Unix()}, now.Add(MatchSignalMaxAge+time.Second))
+	if _, kept := state.signalNonces["n1"]; kept {
+		t.Errorf("nonces of signals past the max age should be forgotten")
+	}
+}
+
+func TestParseMatchSignal(t *testing.T) {
+	if _, err := ParseMatchSignal("not json"); err != ErrSignalBadPayload {
+		t.Errorf("got %v, want bad payload", err)
+	}
+	if _, err := ParseMatchSignal(`{"command":"pause"}`); err != ErrSignalBadPayload {
+		t.Errorf("missing operator: got %v, want bad payload", err)
+	}
+	if _, err := ParseMatchSignal(`{"command":"pause","operator_id":"ops-1"}`); err != ErrSignalBadPayload {
+		t.Errorf("missing nonce: got %v, want bad payload", err)
+	}
+	sig, err := ParseMatchSignal(`{"command":"refund","operator_id":"ops-1","issued_at":1700000000,"nonce":"n1","signature":"ab"}`)
+	if err != nil || sig.Command != SignalRefund || sig.OperatorId != "ops-1" {
+		t.Errorf("got %+v, %v", sig, err)
+	}
+}
+
+func TestRefundRoundBets(t *testing.T) {
+	state := newTestTable()
+	state.userBets["a"] = &pb.BlackjackPlayerBet{UserId: "a", First: 20, Second: 20, Insurance: 10}
+	state.userBets["b"] = &pb.BlackjackPlayerBet{UserId: "b", First: 10}
+	// the bet of b was never charged, its wallet update failed
+	state.RecordStake("a", 20)
+	state.RecordStake("a", 20)
+	state.RecordStake("a", 10)
+
+	refunds := state.RefundRoundBets()
+	if refunds["a"] != 50 || len(refunds) != 1 {
+		t.Errorf("only charged stakes should be refunded, refunds = %v", refunds)
+	}
+	if len(state.GetPlayersBet()) != 0 || !state.IsRoundRefunded() {
+		t.Errorf("bets should be cleared and the round marked refunded")
+	}
+	state.Init()
+	if state.IsRoundRefunded() {
+		t.Errorf("a new round should not be refunded")
+	}
+}
+
+func TestTableStatus(t *testing.T) {
+	state := newTestTable()
+	if state.GetTableStatus() != nil {
+		t.Fatalf("a running table has no status")
+	}
+	state.SetPaused(true, "maintenance")
+	if status := state.GetTableStatus(); status == nil || !status.Paused || status.Reason != "maintenance" {
+		t.Errorf("paused status = %+v", status)
+	}
+	state.SetPaused(false, "")
+	state.SetClosing("shutdown")
+	if status := state.GetTableStatus(); status == nil || status.Paused || !status.Closing {
+		t.Errorf("closing status = %+v", status)
+	}
+}
+
+func TestOperatorKickState(t *testing.T) {
+	state := newTestTable()
+	state.userBets["a"] = &pb.BlackjackPlayerBet{UserId: "a", First: 20}
+	state.RecordStake("a", 20)
+
+	if chips := state.DropRoundBet("a"); chips != 20 || state.IsBet("a") {
+		t.Errorf("dropped %d chips, bet left %v", chips, state.IsBet("a"))
+	}
+	if state.DropRoundBet("a") != 0 {
+		t.Errorf("a bet is refunded once")
+	}
+	state.BanUser("a")
+	state.Init()
+	if !state.IsBanned("a") || state.IsBanned("b") {
+		t.Errorf("a kicked user stays out for the match")
+	}
+}
@@ -3,6 +3,7 @@ package entity
 import (
 	"fmt"
 	"math/rand"
+	"time"
 
 	"github.com/emirpasic/gods/maps/linkedhashmap"
 	"github.com/heroiclabs/nakama-common/runtime"
@@ -56,8 +57,65 @@ type MatchState struct {
 	MatchCount int
 	BotResults map[string]int // Create a map to store individual bot results
 
-	// Bot logic for intelligent betting decisions
-	BotLogic *BlackjackBotLogic
+	// Bot logic for intelligent betting decisions, one per seated bot
+	botLogics map[string]*BlackjackBotLogic
+	// Bankroll session of each seated bot
+	botSessions map[string]*BotSession
+	// Delays bot decisions by a human-like think time
+	botScheduler *BotDecisionScheduler
+	// Last time a human was turned away by a full table
+	humanWaitingAt time.Time
+	// Table chat, emotes and mutes
+	chat *TableChat
+	// Prop bets of presences not playing the round
+	propBets map[string][]*PropBet
+	// Presences watching without a seat and the queue for the next free seat
+	spectators        *linkedhashmap.Map
+	pendingSpectators map[string]time.Time
+	seatQueue         []string
+	// Seat of each user by index, 0 is first base, and the seat users had before leaving
+	seats      []string
+	seatByUser map[string]int
+	lastSeats  map[string]int
+	// Players keeping their seat without playing
+	sitOuts map[string]*sitOut
+	// Betting rounds each player has been idle in a row, players who interacted
+	// since the last count, whether cards were dealt since the last count and why
+	// players are about to be kicked
+	afkRounds     map[string]int
+	afkActive     map[string]bool
+	afkRoundDealt bool
+	kickReasons   map[string]string
+	// Settlement of the last round played, kept after the reward phase
+	lastSettlement *pb.BalanceResult
+	// Players whose seat is held since they dropped mid-round
+	disconnected map[string]time.Time
+	// Deal, reveal and dealer draw steps paced over the following ticks
+	timeline *Timeline
+	// Dealer play of the round being rewarded
+	dealerSequence *DealerSequence
+	// Seconds each player may draw on when a turn runs out
+	timeBanks map[string]*timeBank
+	// Hands given up when the turn ran out
+	forfeits map[string]map[pb.BlackjackHandN0]bool
+	// Hand each player let the turn run out on, played for it since
+	timedOut map[string]pb.BlackjackHandN0
+	// Decision of each player while all players decide at once, nil in turn by turn rounds
+	decisions map[string]*decision
+	// Set by operators, a paused table holds before the next round and a closing one terminates
+	paused       bool
+	closing      bool
+	statusReason string
+	// Stakes of the round were given back by an operator, it is not settled
+	roundRefunded bool
+	// Main bet limits set by operators, nil without any
+	limits *TableLimits
+	// Users kicked by an operator, kept out until the match ends
+	banned map[string]bool
+	// Nonces of the signals applied, by the time they were issued at
+	signalNonces map[string]int64
+	// Chips taken from each wallet for the stakes of the round, bets are charged when placed
+	stakes map[string]int64
 }
 
 func NewMatchState(label *pb.Match) MatchState {
@@ -82,25 +140,68 @@ func NewMatchState(label *pb.Match) MatchState {
 		updateFinish: nil,
 		isGameEnded:  false,
 		BotResults:   make(map[string]int, 0),
-		BotLogic:     NewBlackjackBotLogic(),
+		botLogics:    make(map[string]*BlackjackBotLogic, 0),
+		botSessions:  make(map[string]*BotSession, 0),
+		botScheduler: NewBotDecisionScheduler(),
+		chat:         NewTableChat(),
+		propBets:     make(map[string][]*PropBet),
+		sitOuts:      make(map[string]*sitOut),
+		afkRounds:    make(map[string]int),
+		afkActive:    make(map[string]bool),
+		kickReasons:  make(map[string]string),
+		disconnected: make(map[string]time.Time),
+		timeline:     NewTimeline(),
+		timeBanks:    make(map[string]*timeBank),
+		forfeits:     make(map[string]map[pb.BlackjackHandN0]bool),
+		timedOut:     make(map[string]pb.BlackjackHandN0),
+		stakes:       make(map[string]int64),
+		banned:       make(map[string]bool),
+		signalNonces: make(map[string]int64),
 	}
-	// Automatically add bot players
-	if bots, err := BotLoader.GetFreeBot(int(label.NumBot)); err != nil {
-		fmt.Printf("\r\n load bot failed %s  \r\n", err.Error())
-	} else {
-		m.Bots = bots
+	m.newSpectatorState()
+	m.newSeatState(SeatCountForLabel(label))
+	return m
+}
+
+// SeatInitialBots adds the bots asked by the label of the table, as many as the fill
+// policy lets sit next to the player the table is created for
+func (s *MatchState) SeatInitialBots(logger runtime.Logger) {
+	in := s.BotFillInput()
+	in.Humans = 1
+	numBot := int(s.Label.NumBot)
+	if room := s.BotFillPolicy().BotRoom(in); numBot > room {
+		numBot = room
 	}
-	for _, bot := range m.Bots {
-		m.Presences.Put(bot.GetUserId(), bot)
-		m.Label.Size += 1
+	if numBot <= 0 {
+		return
+	}
+	bots, err := BotLoader.GetFreeBot(numBot)
+	if err != nil {
+		logger.WithField("err", err).Error("load bot failed")
+		return
+	}
+	s.Bots = bots
+	for _, bot := range s.Bots {
+		s.Presences.Put(bot.GetUserId(), bot)
+		s.assignSeat(bot.GetUserId())
+		s.Label.Size += 1
+		s.assignBotLogic(bot)
+		BotPool.Seat(bot.GetUserId(), s.Label.MatchId, int64(s.Label.MarkUnit))
 	}
-	return m
 }
 
 func (s *MatchState) InitUserBet() {
 	for k := range s.userBets {
 		delete(s.userBets, k)
 	}
+	for k := range s.stakes {
+		delete(s.stakes, k)
+	}
+}
+
+// RecordStake counts chips taken from the wallet of userId for a stake of the round
+func (s *MatchState) RecordStake(userId string, chips int64) {
+	s.stakes[userId] += chips
 }
 
 func (s *MatchState) Init() {
@@ -117,14 +218,97 @@ func (s *MatchState) Init() {
 		s.currentHand[presence.GetUserId()] = pb.BlackjackHandN0_BLACKJACK_HAND_1ST
 	}
 	s.isGameEnded = false
+	s.botScheduler.Reset()
+	for k := range s.forfeits {
+		delete(s.forfeits, k)
+	}
+	for k := range s.timedOut {
+		delete(s.timedOut, k)
+	}
+	s.decisions = nil
+	s.roundRefunded = false
+}
+
+// KeepShoe reports whether the shoe of the last round is dealt again until its cut card
+func (s *MatchState) KeepShoe() bool {
+	return PersistentShoe() || s.TableConfig().PersistentShoe
+}
+
+// assignBotLogic gives a newly seated bot its own logic with a weighted personality
+func (s *MatchState) assignBotLogic(botPresence *bot.BotPresence) *BlackjackBotLogic {
+	logic := NewBotLogicWithPersonality(PickBotPersonality())
+	chips := ParseProfile(&botPresence.Account).AccountChip
+	if chips > 0 {
+		logic.SetBalance(chips)
+	}
+	s.botLogics[botPresence.GetUserId()] = logic
+	s.startBotSession(botPresence.GetUserId(), logic.GetBalance())
+	s.botSessions[botPresence.GetUserId()].WalletBalance = chips > 0
+	return logic
+}
+
+// ApplyBotWalletAdjustment syncs a seated bot with its wallet after a house top up or trim.
+// The session restarts from the new balance so the adjustment doesn't count as a win or loss.
+func (s *MatchState) ApplyBotWalletAdjustment(userId string, balance int64) {
+	if logic := s.botLogics[userId]; logic != nil {
+		logic.SetBalance(balance)
+	}
+	if session := s.botSessions[userId]; session != nil {
+		session.StartBalance = balance
+	}
+}
+
+// GetBotLogic returns the logic owned by a seated bot, nil if userId is not a seated bot
+func (s *MatchState) GetBotLogic(userId string) *BlackjackBotLogic {
+	return s.botLogics[userId]
+}
+
+// RecordBotResults feeds the settled round back to each bot's logic
+func (s *MatchState) RecordBotResults(balanceResult *pb.BalanceResult) {
+	if balanceResult == nil {
+		return
+	}
+	for _, update := range balanceResult.Updates {
+		logic := s.botLogics[update.UserId]
+		if logic == nil {
+			continue
+		}
+		logic.SetBalance(update.AmountChipCurrent)
+		logic.RecordResult(update.TotalChipInMatch)
+		s.BotResults[update.UserId] += int(update.TotalChipInMatch)
+		if session := s.botSessions[update.UserId]; session != nil {
+			session.Rounds++
+		}
+	}
+	s.MatchCount++
+}
+
+// OnShoeShuffled resets the card count of counting bots after a new shoe is shuffled
+func (s *MatchState) OnShoeShuffled(shoeSize int) {
+	for _, logic := range s.botLogics {
+		logic.ResetCount(shoeSize)
+	}
+}
+
+// ObserveRoundCards feeds every card shown this round to counting bots
+func (s *MatchState) ObserveRoundCards(cardsRemaining int) {
+	cards := make([]*pb.Card, 0)
+	cards = append(cards, s.dealerHand.first...)
+	for _, h := range s.userHands {
+		cards = append(cards, h.first...)
+		cards = append(cards, h.second...)
+	}
+	for _, logic := range s.botLogics {
+		if !logic.IsCardCounting() {
+			continue
+		}
+		logic.ObserveCards(cards...)
+		logic.SetShoeRemaining(cardsRemaining)
+	}
 }
 
 // InitTurnBot initializes bot turn for betting in preparing phase
 func (s *MatchState) InitTurnBot(botPresence *bot.BotPresence) {
-	// Reset bot logic for new game
-	if s.BotLogic != nil {
-		s.BotLogic.Reset()
-	}
 	preparingTimeout := GameStateDuration[pb.GameState_GAME_STATE_PREPARING].Seconds()
 	opt := bot.TurnOpt{
 		MaxOccur: bot.RandomInt(1, 3),                // Bot will bet 1-3 times during preparing
@@ -392,15 +576,19 @@ func (s *MatchState) IsEnoughPlayer() bool {
 	return s.Presences.Size() >= s.MinPresences
 }
 
-// override
+// override, playing presences are kept in seat order so deals and turns go from first base,
+// players sitting out are skipped
 func (s *MatchState) SetupMatchPresence() {
 	s.PlayingPresences = linkedhashmap.New()
+	s.countSitOutRound()
+	s.afkRoundDealt = true
 	p := make([]runtime.Presence, 0, s.GetPresenceSize())
 	s.Presences.Each(func(key, value interface{}) {
-		if s.IsBet(key.(string)) {
+		if s.IsBet(key.(string)) && !s.IsSittingOut(key.(string)) {
 			p = append(p, value.(runtime.Presence))
 		}
 	})
+	s.SortBySeat(p)
 	s.AddPlayingPresences(p...)
 }
 
@@ -418,6 +606,12 @@ func (s *MatchState) getPlayerBetResult(userId string) *pb.BlackjackPLayerBetRes
 	defer func() { s.userBets[userId].Insurance = 0 }()
 	userBet := s.userBets[userId]
 	r1, r2 := s.userHands[userId].Compare(s.dealerHand)
+	if s.IsForfeited(userId, pb.BlackjackHandN0_BLACKJACK_HAND_1ST) {
+		r1 = -1
+	}
+	if s.IsForfeited(userId, pb.BlackjackHandN0_BLACKJACK_HAND_2ND) {
+		r2 = -1
+	}
 	insurance := &pb.BlackjackBetResult{
 		BetAmount: userBet.Insurance,
 		WinAmount: 0,
@@ -557,12 +751,9 @@ func (s *MatchState) BotTurn(v *bot.BotPresence) error {
 	fmt.Printf("[DEBUG] [BotTurn] Handle bot turn for user: %s\n", userId)
 
 	// Use intelligent bot logic for betting decisions
-	if s.BotLogic != nil {
-		// Set bot balance based on current game state
-		s.BotLogic.SetBalance(int64(s.Label.MarkUnit) * 100) // Assume 100x base unit
-
+	if logic := s.botLogics[userId]; logic != nil {
 		// Generate intelligent bet using bot logic
-		botBet := s.BotLogic.GenerateBotBet()
+		botBet := logic.GenerateBotBet()
 		botBet.UserId = userId
 
 		if botBet.First <= 0 {
@@ -645,8 +836,8 @@ func (s *MatchState) BotInsuranceAction(v *bot.BotPresence) error {
 
 	// Bot decides whether to take insurance
 	var shouldTakeInsurance bool
-	if s.BotLogic != nil {
-		shouldTakeInsurance = s.BotLogic.ShouldTakeInsurance(playerHand, dealerUpCard)
+	if logic := s.botLogics[userId]; logic != nil {
+		shouldTakeInsurance = logic.ShouldTakeInsurance(playerHand, dealerUpCard)
 		fmt.Printf("[DEBUG] [BotInsuranceAction] Bot %s intelligent insurance decision: %v\n", userId, shouldTakeInsurance)
 	} else {
 		// Fallback: random decision (30% chance)
@@ -676,6 +867,93 @@ func (s *MatchState) BotInsuranceAction(v *bot.BotPresence) error {
 	return nil
 }
 
+// ScheduleBotInsurance queues the bot's insurance decision after a think time
+func (s *MatchState) ScheduleBotInsurance(logger runtime.Logger, v *bot.BotPresence) bool {
+	userId := v.GetUserId()
+	key := userId + ":insurance"
+	if s.botScheduler.IsScheduled(key) {
+		return false
+	}
+	delay := s.botThinkTime(userId, DecisionNormal)
+	logger.WithField("user_id", userId).WithField("delay", delay.String()).Debug("bot insurance decision scheduled")
+	return s.botScheduler.Schedule(userId, key, delay, func() bool {
+		if !s.IsAllowInsurance() || s.HasInsuranceBet(userId) {
+			return false
+		}
+		// declining insurance queues nothing and is final
+		queued := len(s.messages)
+		s.BotInsuranceAction(v)
+		return len(s.messages) > queued
+	})
+}
+
+// ScheduleBotAction queues the bot's decision on its current hand after a think time.
+// A new decision is scheduled each time the hand changes.
+func (s *MatchState) ScheduleBotAction(logger runtime.Logger, v *bot.BotPresence) bool {
+	userId := v.GetUserId()
+	handN0 := s.currentHand[userId]
+	playerHand := s.GetPlayerPartOfHand(userId, handN0)
+	numCards := 0
+	if playerHand != nil {
+		numCards = len(playerHand.Cards)
+	}
+	key := fmt.Sprintf("%s:action:%d:%d", userId, handN0, numCards)
+	if s.botScheduler.IsScheduled(key) {
+		return false
+	}
+	legalActions := s.GetLegalActionsByUserId(userId)
+	if len(legalActions) == 0 {
+		return false
+	}
+	difficulty := ClassifyDecision(playerHand, s.dealerUpCard(), legalActions)
+	delay := s.botThinkTime(userId, difficulty)
+	logger.WithField("user_id", userId).WithField("delay", delay.String()).WithField("difficulty", difficulty).
+		Debug("bot action scheduled")
+	return s.botScheduler.Schedule(userId, key, delay, func() bool {
+		if !s.IsAllowAction() || !s.IsInTurn(userId) {
+			return false
+		}
+		if legalActions := s.GetLegalActionsByUserId(userId); len(legalActions) > 0 {
+			return s.BotAction(v, legalActions) == nil
+		}
+		return false
+	})
+}
+
+// RunBotDecisions fires the scheduled bot decisions whose think time has elapsed
+func (s *MatchState) RunBotDecisions() int {
+	return s.botScheduler.RunDue()
+}
+
+// AcceptBotDecision marks the decisions sent for userId done, the engine applied their action
+func (s *MatchState) AcceptBotDecision(userId string) {
+	s.botScheduler.Accept(userId)
+}
+
+// SettleBotDecisions lets the decisions whose action the engine refused be scheduled again,
+// called once the queued messages of the tick have been processed
+func (s *MatchState) SettleBotDecisions() {
+	s.botScheduler.Settle()
+}
+
+// botThinkTime draws a think time that ends before the current countdown does.
+// The countdown is the one set from the turn engine at the start of each turn.
+func (s *MatchState) botThinkTime(userId string, difficulty DecisionDifficulty) time.Duration {
+	var personality BotPersonality
+	if logic := s.botLogics[userId]; logic != nil {
+		personality = logic.GetPersonality()
+	}
+	remain := time.Duration(s.GetRemainCountDown() * float64(time.Second))
+	return ClampThinkTime(ThinkTime(personality, difficulty), remain)
+}
+
+func (s *MatchState) dealerUpCard() *pb.Card {
+	if s.dealerHand != nil && len(s.dealerHand.first) > 0 {
+		return s.dealerHand.first[0]
+	}
+	return nil
+}
+
 // BotAction handles bot action decisions during game play and sends via message queue
 func (s *MatchState) BotAction(v *bot.BotPresence, legalActions []pb.BlackjackActionCode) error {
 	userId := v.GetUserId()
@@ -683,9 +961,10 @@ func (s *MatchState) BotAction(v *bot.BotPresence, legalActions []pb.BlackjackAc
 	fmt.Printf("[DEBUG] [BotAction] Handle bot action for user: %s, legal actions: %v\n", userId, legalActions)
 
 	var action pb.BlackjackActionCode
+	logic := s.botLogics[userId]
 
 	// Use intelligent bot logic for action decisions
-	if s.BotLogic != nil {
+	if logic != nil {
 		// Get current player hand
 		playerHand := s.GetPlayerPartOfHand(userId, s.currentHand[userId])
 		if playerHand == nil {
@@ -704,7 +983,7 @@ func (s *MatchState) BotAction(v *bot.BotPresence, legalActions []pb.BlackjackAc
 			}
 
 			// Decide action using bot logic
-			action = s.BotLogic.DecideGameAction(playerHand, dealerUpCard, legalActions)
+			action = logic.DecideGameAction(playerHand, dealerUpCard, legalActions)
 
 			fmt.Printf("[DEBUG] [BotAction] Bot %s intelligent action decision: %v\n", userId, action)
 		}
@@ -725,8 +1004,8 @@ func (s *MatchState) BotAction(v *bot.BotPresence, legalActions []pb.BlackjackAc
 	}
 
 	// Add action to history
-	if s.BotLogic != nil {
-		s.BotLogic.AddActionHistory(actionMessage)
+	if logic != nil {
+		logic.AddActionHistory(actionMessage)
 	}
 
 	// Marshal and send via message queue like a real user
@@ -0,0 +1,78 @@
+package entity
+
+// Opcodes of the messages this module adds next to the ones of the shared proto,
+// their payloads are JSON since they are not part of the shared proto
+
+// Table chat, emotes, mutes and reports
+const (
+	OpCodeRequestChat   = 500
+	OpCodeRequestEmote  = 501
+	OpCodeRequestMute   = 502
+	OpCodeRequestReport = 503
+
+	OpCodeUpdateChat      = 600
+	OpCodeUpdateEmote     = 601
+	OpCodeUpdateChatError = 602
+)
+
+// Prop bets
+const (
+	OpCodeRequestPropBet = 510
+
+	OpCodeUpdatePropBet       = 610
+	OpCodeUpdatePropBetResult = 611
+	OpCodeUpdatePropBetError  = 612
+)
+
+// Seat queue of spectators
+const (
+	OpCodeRequestSeatQueue = 520
+
+	OpCodeUpdateSeatQueue    = 620
+	OpCodeUpdateSeatPromoted = 621
+)
+
+// Seat choice
+const (
+	OpCodeRequestSitSeat = 530
+
+	OpCodeUpdateSeatError = 631
+)
+
+// Sit-out
+const (
+	OpCodeRequestSitOut = 540
+
+	OpCodeUpdateSitOut      = 640
+	OpCodeUpdateSitOutError = 641
+)
+
+// AFK warnings and kicks
+const (
+	OpCodeUpdateAfkWarning = 650
+	OpCodeUpdateKickReason = 651
+)
+
+// OpCodeUpdateTableSnapshot carries the whole table
+const OpCodeUpdateTableSnapshot = 660
+
+// OpCodeUpdateDisconnected tells the table a player dropped or came back
+const OpCodeUpdateDisconnected = 670
+
+// OpCodeUpdateTimeline announces the next step of the deal timeline
+const OpCodeUpdateTimeline = 680
+
+// OpCodeUpdateDealerStep carries a step of the dealer play
+const OpCodeUpdateDealerStep = 690
+
+// OpCodeUpdateAction carries an action played for a player
+const OpCodeUpdateAction = 710
+
+// OpCodeUpdateDecisions lists who is still deciding in a simultaneous round
+const OpCodeUpdateDecisions = 720
+
+// OpCodeUpdateTableStatus tells clients the table is paused or closing
+const OpCodeUpdateTableStatus = 730
+
+// OpCodeUpdateTableLimits carries the main bet limits of the table
+const OpCodeUpdateTableLimits = 740
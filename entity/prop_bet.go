@@ -0,0 +1,193 @@
+package entity
+
+import (
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+// PropBetKind is a proposition on the outcome of the round
+type PropBetKind string
+
+const (
+	// dealer ends the round over 21
+	PropBetDealerBust PropBetKind = "dealer_bust"
+	// dealer ends the round on 20 or 21
+	PropBetDealer20Plus PropBetKind = "dealer_20_plus"
+	// a seated player is dealt a natural blackjack
+	PropBetAnyNatural PropBetKind = "any_natural"
+)
+
+// PropBetOdds pays odds to 1 on a winning prop bet
+var PropBetOdds = map[PropBetKind]int64{
+	PropBetDealerBust:   2,
+	PropBetDealer20Plus: 2,
+	PropBetAnyNatural:   5,
+}
+
+const (
+	// PropBetMaxUnits is the largest prop bet of one kind, in MarkUnit
+	PropBetMaxUnits = 10
+	// PropBetMaxTotalUnits is the largest total of prop bets of a user in a round, in MarkUnit
+	PropBetMaxTotalUnits = 20
+)
+
+// Prop bet error codes sent back to the bettor
+const (
+	PropBetErrorClosed        = "betting_closed"
+	PropBetErrorSeated        = "seated_player"
+	PropBetErrorUnknownKind   = "unknown_kind"
+	PropBetErrorBelowMin      = "below_min"
+	PropBetErrorAboveMax      = "above_max"
+	PropBetErrorNotEnoughChip = "not_enough_chip"
+)
+
+type PropBetRequest struct {
+	Kind  PropBetKind `json:"kind"`
+	Chips int64       `json:"chips"`
+}
+
+type PropBet struct {
+	Kind  PropBetKind `json:"kind"`
+	Chips int64       `json:"chips"`
+}
+
+type PropBetUpdate struct {
+	UserId string     `json:"user_id"`
+	Bets   []*PropBet `json:"bets"`
+}
+
+type PropBetError struct {
+	Code string `json:"code"`
+}
+
+// PropBetOutcome is what prop bets are settled against
+type PropBetOutcome struct {
+	DealerBust  bool `json:"dealer_bust"`
+	DealerTotal int  `json:"dealer_total"`
+	AnyNatural  bool `json:"any_natural"`
+}
+
+// Wins reports whether a prop bet of kind wins with this outcome
+func (o PropBetOutcome) Wins(kind PropBetKind) bool {
+	switch kind {
+	case PropBetDealerBust:
+		return o.DealerBust
+	case PropBetDealer20Plus:
+		return !o.DealerBust && o.DealerTotal >= 20
+	case PropBetAnyNatural:
+		return o.AnyNatural
+	}
+	return false
+}
+
+type PropBetSettled struct {
+	Kind   PropBetKind `json:"kind"`
+	Chips  int64       `json:"chips"`
+	Win    bool        `json:"win"`
+	Payout int64       `json:"payout"`
+}
+
+// PropBetResult is the settlement of every prop bet of a user
+type PropBetResult struct {
+	UserId      string            `json:"user_id"`
+	Outcome     PropBetOutcome    `json:"outcome"`
+	Bets        []*PropBetSettled `json:"bets"`
+	TotalBet    int64             `json:"total_bet"`
+	TotalPayout int64             `json:"total_payout"`
+}
+
+// CanPlacePropBet checks a prop bet, returns an error code when it is refused
+func (s *MatchState) CanPlacePropBet(userId string, bet *PropBetRequest, balance int64) string {
+	if !s.IsAllowBet() {
+		return PropBetErrorClosed
+	}
+	if s.IsBet(userId) || s.IsBot(userId) {
+		return PropBetErrorSeated
+	}
+	if _, ok := PropBetOdds[bet.Kind]; !ok {
+		return PropBetErrorUnknownKind
+	}
+	markUnit := int64(s.Label.MarkUnit)
+	if bet.Chips <= 0 || bet.Chips < markUnit {
+		return PropBetErrorBelowMin
+	}
+	sameKind, total := int64(0), int64(0)
+	for _, v := range s.propBets[userId] {
+		total += v.Chips
+		if v.Kind == bet.Kind {
+			sameKind += v.Chips
+		}
+	}
+	if sameKind+bet.Chips > markUnit*PropBetMaxUnits || total+bet.Chips > markUnit*PropBetMaxTotalUnits {
+		return PropBetErrorAboveMax
+	}
+	if bet.Chips > balance {
+		return PropBetErrorNotEnoughChip
+	}
+	return ""
+}
+
+// AddPropBet records a prop bet, bets of the same kind are merged
+func (s *MatchState) AddPropBet(userId string, kind PropBetKind, chips int64) {
+	for _, v := range s.propBets[userId] {
+		if v.Kind == kind {
+			v.Chips += chips
+			return
+		}
+	}
+	s.propBets[userId] = append(s.propBets[userId], &PropBet{Kind: kind, Chips: chips})
+}
+
+// GetPropBets returns the prop bets of a user this round
+func (s *MatchState) GetPropBets(userId string) []*PropBet {
+	return s.propBets[userId]
+}
+
+// GetPropBettors returns the users with prop bets this round
+func (s *MatchState) GetPropBettors() []string {
+	userIds := make([]string, 0, len(s.propBets))
+	for userId := range s.propBets {
+		userIds = append(userIds, userId)
+	}
+	return userIds
+}
+
+// PropBetOutcome reads the outcome of the finished round from the hands
+func (s *MatchState) PropBetOutcome() PropBetOutcome {
+	point, _, handType := s.dealerHand.Eval(pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
+	outcome := PropBetOutcome{
+		DealerBust:  handType == pb.BlackjackHandType_BLACKJACK_HAND_TYPE_BUSTED,
+		DealerTotal: point.Point,
+	}
+	for _, h := range s.userHands {
+		if _, _, t := h.Eval(pb.BlackjackHandN0_BLACKJACK_HAND_1ST); t == pb.BlackjackHandType_BLACKJACK_HAND_TYPE_BLACKJACK {
+			outcome.AnyNatural = true
+			break
+		}
+	}
+	return outcome
+}
+
+// SettlePropBets settles every prop bet of the round against the outcome, payouts include the stake
+func (s *MatchState) SettlePropBets(outcome PropBetOutcome) []*PropBetResult {
+	results := make([]*PropBetResult, 0, len(s.propBets))
+	for userId, bets := range s.propBets {
+		result := &PropBetResult{UserId: userId, Outcome: outcome}
+		for _, bet := range bets {
+			settled := &PropBetSettled{Kind: bet.Kind, Chips: bet.Chips}
+			if outcome.Wins(bet.Kind) {
+				settled.Win = true
+				settled.Payout = bet.Chips * (PropBetOdds[bet.Kind] + 1)
+			}
+			result.Bets = append(result.Bets, settled)
+			result.TotalBet += bet.Chips
+			result.TotalPayout += settled.Payout
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// ResetPropBets clears the prop bets once they are settled or refunded
+func (s *MatchState) ResetPropBets() {
+	s.propBets = make(map[string][]*PropBet)
+}
@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"testing"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func TestCanPlacePropBet(t *testing.T) {
+	state := NewMatchState(&pb.Match{MarkUnit: 100})
+	bet := &PropBetRequest{Kind: PropBetDealerBust, Chips: 100}
+	if code := state.CanPlacePropBet("u1", bet, 10000); code != PropBetErrorClosed {
+		t.Errorf("Expected betting closed, got %q", code)
+	}
+	state.SetAllowBet(true)
+
+	testCases := []struct {
+		name     string
+		bet      *PropBetRequest
+		balance  int64
+		expected string
+	}{
+		{"valid", &PropBetRequest{Kind: PropBetDealerBust, Chips: 100}, 10000, ""},
+		{"unknown kind", &PropBetRequest{Kind: "dealer_smiles", Chips: 100}, 10000, PropBetErrorUnknownKind},
+		{"below min", &PropBetRequest{Kind: PropBetDealerBust, Chips: 50}, 10000, PropBetErrorBelowMin},
+		{"above max", &PropBetRequest{Kind: PropBetDealerBust, Chips: 1100}, 10000, PropBetErrorAboveMax},
+		{"not enough chip", &PropBetRequest{Kind: PropBetDealerBust, Chips: 500}, 400, PropBetErrorNotEnoughChip},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if code := state.CanPlacePropBet("u1", tc.bet, tc.balance); code != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, code)
+			}
+		})
+	}
+
+	// limits count the bets already placed
+	state.AddPropBet("u1", PropBetDealerBust, 900)
+	state.AddPropBet("u1", PropBetAnyNatural, 1000)
+	if code := state.CanPlacePropBet("u1", &PropBetRequest{Kind: PropBetDealerBust, Chips: 200}, 10000); code != PropBetErrorAboveMax {
+		t.Errorf("Expected per kind limit, got %q", code)
+	}
+	if code := state.CanPlacePropBet("u1", &PropBetRequest{Kind: PropBetDealer20Plus, Chips: 200}, 10000); code != PropBetErrorAboveMax {
+		t.Errorf("Expected round total limit, got %q", code)
+	}
+
+	state.botLogics["bot1"] = NewBotLogicWithPersonality(BotPersonalityNovice)
+	if code := state.CanPlacePropBet("bot1", bet, 10000); code != PropBetErrorSeated {
+		t.Errorf("Expected bots to be refused, got %q", code)
+	}
+}
+
+func TestSettlePropBets(t *testing.T) {
+	state := NewMatchState(&pb.Match{MarkUnit: 100})
+	state.AddPropBet("u1", PropBetDealerBust, 100)
+	state.AddPropBet("u1", PropBetDealerBust, 100)
+	state.AddPropBet("u1", PropBetAnyNatural, 100)
+	state.AddPropBet("u2", PropBetDealer20Plus, 300)
+	if bets := state.GetPropBets("u1"); len(bets) != 2 || bets[0].Chips != 200 {
+		t.Fatalf("Expected bets of a kind to be merged, got %+v", bets)
+	}
+
+	results := state.SettlePropBets(PropBetOutcome{DealerBust: true, DealerTotal: 24})
+	byUser := make(map[string]*PropBetResult)
+	for _, r := range results {
+		byUser[r.UserId] = r
+	}
+	if r := byUser["u1"]; r.TotalBet != 300 || r.TotalPayout != 600 {
+		t.Errorf("Expected dealer bust to pay 2 to 1, got %+v", r)
+	}
+	if r := byUser["u2"]; r.TotalBet != 300 || r.TotalPayout != 0 {
+		t.Errorf("Expected busted dealer to lose 20+, got %+v", r)
+	}
+
+	state.ResetPropBets()
+	if len(state.GetPropBettors()) != 0 {
+		t.Errorf("Expected prop bets to be cleared")
+	}
+}
+
+func TestPropBetOutcomeWins(t *testing.T) {
+	outcome := PropBetOutcome{DealerTotal: 20, AnyNatural: true}
+	if !outcome.Wins(PropBetDealer20Plus) || !outcome.Wins(PropBetAnyNatural) || outcome.Wins(PropBetDealerBust) {
+		t.Errorf("Unexpected wins for %+v", outcome)
+	}
+	if (PropBetOutcome{DealerTotal: 19}).Wins(PropBetDealer20Plus) {
+		t.Errorf("Expected 19 to lose 20+")
+	}
+}
@@ -0,0 +1,118 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+// NotificationCodeRoundSummary is the Nakama notification code of a round settled while away
+const NotificationCodeRoundSummary = 1301
+
+// Dealer results of a round summary
+const (
+	DealerResultBust      = "bust"
+	DealerResultBlackjack = "blackjack"
+	DealerResultStand     = "stand"
+)
+
+// RoundSummary tells a player how a round settled while they were away from the table
+type RoundSummary struct {
+	MatchId   string `json:"match_id"`
+	Game      string `json:"game"`
+	MarkUnit  int64  `json:"mark_unit"`
+	SettledAt int64  `json:"settled_at"`
+
+	Hand         *pb.BlackjackPlayerHand      `json:"hand"`
+	DealerHand   *pb.BlackjackPlayerHand      `json:"dealer_hand"`
+	DealerResult string                       `json:"dealer_result"`
+	DealerPoint  int32                        `json:"dealer_point"`
+	Result       *pb.BlackjackPLayerBetResult `json:"result"`
+
+	Bet int64 `json:"bet"`
+	// Win is paid back before the fee, Net is what the round changed in the wallet
+	Win        int64 `json:"win"`
+	Fee        int64 `json:"fee"`
+	Net        int64 `json:"net"`
+	ChipsAfter int64 `json:"chips_after"`
+}
+
+// AbsentSettledPlayers returns the settled players who left or dropped before the reward
+func (s *MatchState) AbsentSettledPlayers(balanceResult *pb.BalanceResult) []*pb.BalanceUpdate {
+	absent := make([]*pb.BalanceUpdate, 0)
+	if balanceResult == nil {
+		return absent
+	}
+	for _, update := range balanceResult.Updates {
+		userId := update.UserId
+		if s.IsBot(userId) {
+			continue
+		}
+		if _, leaving := s.LeavePresences.Get(userId); leaving || s.IsDisconnected(userId) || s.GetPresence(userId) == nil {
+			absent = append(absent, update)
+		}
+	}
+	return absent
+}
+
+// RoundSummary builds the summary of the finished round for one settled player
+func (s *MatchState) RoundSummary(update *pb.BalanceUpdate) *RoundSummary {
+	summary := &RoundSummary{
+		MatchId:    s.Label.MatchId,
+		Game:       s.Label.Name,
+		MarkUnit:   int64(s.Label.MarkUnit),
+		SettledAt:  time.Now().Unix(),
+		DealerHand: s.GetDealerHand(),
+		Bet:        update.AmoutChipBet,
+		Net:        update.TotalChipInMatch,
+		ChipsAfter: update.AmountChipCurrent,
+	}
+	if hand := s.PlayerHand(update.UserId); hand != nil {
+		summary.Hand = hand.ToPb()
+	}
+	if first := summary.DealerHand.First; first != nil {
+		summary.DealerPoint = first.Point
+		summary.DealerResult = dealerResult(first)
+	}
+	if finish := s.GetUpdateFinish(); finish != nil {
+		for _, result := range finish.BetResults {
+			if result.UserId != update.UserId {
+				continue
+			}
+			summary.Result = result
+			for _, r := range []*pb.BlackjackBetResult{result.First, result.Second, result.Insurance} {
+				if r != nil {
+					summary.Win += r.Total
+				}
+			}
+		}
+	}
+	if fee := summary.Win - update.AmountChipAdd; summary.Win > 0 && fee > 0 {
+		summary.Fee = fee
+	}
+	return summary
+}
+
+// Content returns the summary as the content of a Nakama notification
+func (r *RoundSummary) Content() map[string]interface{} {
+	content := make(map[string]interface{})
+	buf, err := json.Marshal(r)
+	if err != nil {
+		return content
+	}
+	json.Unmarshal(buf, &content)
+	return content
+}
+
+// dealerResult tells how the dealer hand ended
+func dealerResult(hand *pb.BlackjackHand) string {
+	switch hand.Type {
+	case pb.BlackjackHandType_BLACKJACK_HAND_TYPE_BUSTED:
+		return DealerResultBust
+	case pb.BlackjackHandType_BLACKJACK_HAND_TYPE_BLACKJACK:
+		return DealerResultBlackjack
+	default:
+		return DealerResultStand
+	}
+}
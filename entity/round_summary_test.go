@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"testing"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func TestAbsentSettledPlayers(t *testing.T) {
+	state := newTestTable("p1", "p2", "p3")
+	state.AddLeavePresence(&FakePrecense{UserId: "p2"})
+	state.MarkDisconnected("p3")
+	result := &pb.BalanceResult{Updates: []*pb.BalanceUpdate{
+		{UserId: "p1"}, {UserId: "p2"}, {UserId: "p3"}, {UserId: "gone"},
+	}}
+
+	absent := state.AbsentSettledPlayers(result)
+	if len(absent) != 3 || absent[0].UserId != "p2" || absent[1].UserId != "p3" || absent[2].UserId != "gone" {
+		t.Errorf("Unexpected absent players %+v", absent)
+	}
+	if len(state.AbsentSettledPlayers(nil)) != 0 {
+		t.Errorf("Expected nobody without a settlement")
+	}
+}
+
+func TestRoundSummary(t *testing.T) {
+	state := NewMatchState(&pb.Match{MatchId: "m1", MarkUnit: 100})
+	state.AddCards([]*pb.Card{
+		{Rank: pb.CardRank_RANK_10, Suit: pb.CardSuit_SUIT_SPADES},
+		{Rank: pb.CardRank_RANK_6, Suit: pb.CardSuit_SUIT_HEARTS},
+		{Rank: pb.CardRank_RANK_K, Suit: pb.CardSuit_SUIT_CLUBS},
+	}, "", pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
+	state.AddCards([]*pb.Card{
+		{Rank: pb.CardRank_RANK_10, Suit: pb.CardSuit_SUIT_CLUBS},
+		{Rank: pb.CardRank_RANK_9, Suit: pb.CardSuit_SUIT_DIAMONDS},
+	}, "p1", pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
+	state.SetUpdateFinish(&pb.BlackjackUpdateFinish{BetResults: []*pb.BlackjackPLayerBetResult{{
+		UserId:    "p1",
+		First:     &pb.BlackjackBetResult{BetAmount: 100, Total: 200},
+		Second:    &pb.BlackjackBetResult{},
+		Insurance: &pb.BlackjackBetResult{},
+	}}})
+
+	summary := state.RoundSummary(&pb.BalanceUpdate{
+		UserId: "p1", AmoutChipBet: 100, AmountChipAdd: 190, TotalChipInMatch: 90, AmountChipCurrent: 1090,
+	})
+	if summary.MatchId != "m1" || summary.Hand == nil || summary.Result == nil {
+		t.Fatalf("Unexpected summary %+v", summary)
+	}
+	if summary.DealerResult != DealerResultBust {
+		t.Errorf("Expected the dealer to bust, got %s", summary.DealerResult)
+	}
+	if summary.Win != 200 || summary.Fee != 10 || summary.Net != 90 {
+		t.Errorf("Unexpected amounts win %d fee %d net %d", summary.Win, summary.Fee, summary.Net)
+	}
+	if content := summary.Content(); content["match_id"] != "m1" || content["fee"] != float64(10) {
+		t.Errorf("Unexpected notification content %v", content)
+	}
+}
@@ -0,0 +1,204 @@
+package entity
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+// Seat error codes sent back to the requester
+const (
+	SeatErrorInvalid = "invalid_seat"
+	SeatErrorTaken   = "seat_taken"
+	SeatErrorPlaying = "playing_round"
+	SeatErrorQueue   = "seat_queue_not_empty"
+	SeatErrorChips   = "chips_not_enough"
+)
+
+type SitSeatRequest struct {
+	// Seat number, 1 is first base
+	Seat int `json:"seat"`
+}
+
+type SeatInfo struct {
+	Seat   int    `json:"seat"`
+	UserId string `json:"user_id"`
+}
+
+// SeatsUpdate lists the taken seats of the table
+type SeatsUpdate struct {
+	Count int         `json:"count"`
+	Seats []*SeatInfo `json:"seats"`
+}
+
+type SeatError struct {
+	Code string `json:"code"`
+}
+
+var (
+	tableSeatsMu sync.RWMutex
+	tableSeats   = MaxPresences
+)
+
+// SetTableSeats sets the number of seats of tables not asking for their own, 5 or 7
+func SetTableSeats(n int) error {
+	if n != 5 && n != 7 {
+		return fmt.Errorf("table seats must be 5 or 7, got %d", n)
+	}
+	tableSeatsMu.Lock()
+	defer tableSeatsMu.Unlock()
+	tableSeats = n
+	return nil
+}
+
+// SeatCountForLabel returns the seats of a table, from its MaxSize when it is 5 or 7
+func SeatCountForLabel(label *pb.Match) int {
+	if label != nil && (label.MaxSize == 5 || label.MaxSize == 7) {
+		return int(label.MaxSize)
+	}
+	tableSeatsMu.RLock()
+	defer tableSeatsMu.RUnlock()
+	return tableSeats
+}
+
+// assignSeat seats userId on its previous seat when free, else on the first free seat from first base
+func (s *MatchState) assignSeat(userId string) int {
+	if seat, ok := s.seatByUser[userId]; ok {
+		return seat
+	}
+	seat := -1
+	if last, ok := s.lastSeats[userId]; ok && last < len(s.seats) && s.seats[last] == "" {
+		seat = last
+	} else {
+		for i, v := range s.seats {
+			if v == "" {
+				seat = i
+				break
+			}
+		}
+	}
+	if seat < 0 {
+		return seat
+	}
+	s.seats[seat] = userId
+	s.seatByUser[userId] = seat
+	delete(s.lastSeats, userId)
+	return seat
+}
+
+// releaseSeat frees the seat of userId, remembered for when the user comes back.
+// A seat is only kept for the last user who left it, so at most one user per seat is remembered.
+func (s *MatchState) releaseSeat(userId string) {
+	seat, ok := s.seatByUser[userId]
+	if !ok {
+		return
+	}
+	s.seats[seat] = ""
+	delete(s.seatByUser, userId)
+	for other, last := range s.lastSeats {
+		if last == seat {
+			delete(s.lastSeats, other)
+		}
+	}
+	s.lastSeats[userId] = seat
+}
+
+// GetSeat returns the seat number of userId, 0 when not seated
+func (s *MatchState) GetSeat(userId string) int {
+	if seat, ok := s.seatByUser[userId]; ok {
+		return seat + 1
+	}
+	return 0
+}
+
+// GetSeats returns the taken seats from first base
+func (s *MatchState) GetSeats() *SeatsUpdate {
+	update := &SeatsUpdate{Count: len(s.seats), Seats: make([]*SeatInfo, 0)}
+	for i, userId := range s.seats {
+		if userId != "" {
+			update.Seats = append(update.Seats, &SeatInfo{Seat: i + 1, UserId: userId})
+		}
+	}
+	return update
+}
+
+// SitAtSeat moves a seated user not playing the round to a free seat
+func (s *MatchState) SitAtSeat(userId string, seat int) string {
+	if seat < 1 || seat > len(s.seats) {
+		return SeatErrorInvalid
+	}
+	if owner := s.seats[seat-1]; owner != "" && owner != userId {
+		return SeatErrorTaken
+	}
+	if _, playing := s.PlayingPresences.Get(userId); playing || s.IsBet(userId) {
+		return SeatErrorPlaying
+	}
+	if current, ok := s.seatByUser[userId]; ok {
+		s.seats[current] = ""
+	}
+	s.seats[seat-1] = userId
+	s.seatByUser[userId] = seat - 1
+	return ""
+}
+
+// IsSeatFree reports whether a seat number is free
+func (s *MatchState) IsSeatFree(seat int) bool {
+	return seat >= 1 && seat <= len(s.seats) && s.seats[seat-1] == ""
+}
+
+// seatOrder returns the seat index of userId, unseated users last
+func (s *MatchState) seatOrder(userId string) int {
+	if seat, ok := s.seatByUser[userId]; ok {
+		return seat
+	}
+	return len(s.seats)
+}
+
+// SortBySeat orders presences from first base, unseated presences last
+func (s *MatchState) SortBySeat(presences []runtime.Presence) {
+	sort.SliceStable(presences, func(i, j int) bool {
+		return s.seatOrder(presences[i].GetUserId()) < s.seatOrder(presences[j].GetUserId())
+	})
+}
+
+func (s *MatchState) newSeatState(count int) {
+	s.MaxPresences = count
+	s.seats = make([]string, count)
+	s.seatByUser = make(map[string]int)
+	s.lastSeats = make(map[string]int)
+}
+
+// override, seats the new presences
+func (s *MatchState) AddPresence(ctx context.Context, nk runtime.NakamaModule,
+	db *sql.DB,
+	presences []runtime.Presence,
+) {
+	s.baseMatchState.AddPresence(ctx, nk, db, presences)
+	for _, presence := range presences {
+		s.assignSeat(presence.GetUserId())
+		s.afkActive[presence.GetUserId()] = true
+	}
+}
+
+// override, frees the seats of removed presences
+func (s *MatchState) RemovePresences(presences ...runtime.Presence) {
+	s.baseMatchState.RemovePresences(presences...)
+	for _, presence := range presences {
+		s.releaseSeat(presence.GetUserId())
+		s.forgetAfk(presence.GetUserId())
+	}
+}
+
+// override, frees the seats of presences that left during the round
+func (s *MatchState) ApplyLeavePresence() {
+	for _, presence := range s.GetLeavePresences() {
+		s.releaseSeat(presence.GetUserId())
+		s.forgetAfk(presence.GetUserId())
+	}
+	s.baseMatchState.ApplyLeavePresence()
+}
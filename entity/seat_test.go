@@ -0,0 +1,110 @@
+package entity
+
+import (
+	"testing"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func TestSeatCount(t *testing.T) {
+	if n := SeatCountForLabel(&pb.Match{MaxSize: 7}); n != 7 {
+		t.Errorf("Expected 7 seats from the label, got %d", n)
+	}
+	if n := SeatCountForLabel(&pb.Match{MaxSize: 6}); n != MaxPresences {
+		t.Errorf("Expected default seats for an unsupported size, got %d", n)
+	}
+	if err := SetTableSeats(6); err == nil {
+		t.Errorf("Expected 6 seats to be refused")
+	}
+	if err := SetTableSeats(7); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer SetTableSeats(MaxPresences)
+	state := newTestTable()
+	if state.MaxPresences != 7 || len(state.seats) != 7 {
+		t.Errorf("Expected a 7 seat table, got %d", state.MaxPresences)
+	}
+}
+
+func TestSeatAssignAndRejoin(t *testing.T) {
+	state := newTestTable("p1", "p2", "p3")
+	if state.GetSeat("p1") != 1 || state.GetSeat("p3") != 3 {
+		t.Errorf("Expected seats from first base, got %d %d", state.GetSeat("p1"), state.GetSeat("p3"))
+	}
+
+	state.RemovePresences(&FakePrecense{UserId: "p2"})
+	if state.GetSeat("p2") != 0 || !state.IsSeatFree(2) {
+		t.Errorf("Expected seat 2 to be free")
+	}
+	state.assignSeat("p4")
+	if state.GetSeat("p4") != 2 {
+		t.Errorf("Expected p4 on the first free seat, got %d", state.GetSeat("p4"))
+	}
+	state.RemovePresences(&FakePrecense{UserId: "p3"})
+	state.assignSeat("p2")
+	if state.GetSeat("p2") != 3 {
+		t.Errorf("Expected p2 on the first free seat while its seat is taken, got %d", state.GetSeat("p2"))
+	}
+	state.assignSeat("p3")
+	if state.GetSeat("p3") != 4 {
+		t.Errorf("Expected p3 on seat 4, got %d", state.GetSeat("p3"))
+	}
+
+	state.RemovePresences(&FakePrecense{UserId: "p3"})
+	state.assignSeat("p3")
+	if state.GetSeat("p3") != 4 {
+		t.Errorf("Expected p3 back on its seat, got %d", state.GetSeat("p3"))
+	}
+	if _, kept := state.lastSeats["p3"]; kept {
+		t.Errorf("Expected the remembered seat to be dropped once taken back")
+	}
+
+	state.RemovePresences(&FakePrecense{UserId: "p1"})
+	state.assignSeat("p5")
+	state.RemovePresences(&FakePrecense{UserId: "p5"})
+	if _, kept := state.lastSeats["p1"]; kept || len(state.lastSeats) != 1 {
+		t.Errorf("Expected a seat to be remembered for its last user only, got %v", state.lastSeats)
+	}
+}
+
+func TestSitAtSeat(t *testing.T) {
+	state := newTestTable()
+	state.assignSeat("p1")
+	state.assignSeat("p2")
+
+	if code := state.SitAtSeat("p1", 6); code != SeatErrorInvalid {
+		t.Errorf("Expected invalid seat, got %q", code)
+	}
+	if code := state.SitAtSeat("p1", 2); code != SeatErrorTaken {
+		t.Errorf("Expected seat taken, got %q", code)
+	}
+	if code := state.SitAtSeat("p1", 5); code != "" {
+		t.Fatalf("Unexpected error %q", code)
+	}
+	if state.GetSeat("p1") != 5 || !state.IsSeatFree(1) {
+		t.Errorf("Expected p1 moved to seat 5")
+	}
+
+	state.userBets["p2"] = &pb.BlackjackPlayerBet{UserId: "p2", First: 100}
+	if code := state.SitAtSeat("p2", 1); code != SeatErrorPlaying {
+		t.Errorf("Expected a player with a bet to keep the seat, got %q", code)
+	}
+}
+
+func TestSetupMatchPresenceSeatOrder(t *testing.T) {
+	state := newTestTable("p1", "p2", "p3")
+	state.SitAtSeat("p2", 5)
+	state.SitAtSeat("p1", 4)
+	for _, id := range []string{"p1", "p2", "p3"} {
+		state.userBets[id] = &pb.BlackjackPlayerBet{UserId: id, First: 100}
+	}
+
+	state.SetupMatchPresence()
+	order := make([]string, 0)
+	for _, p := range state.GetPlayingPresences() {
+		order = append(order, p.GetUserId())
+	}
+	if len(order) != 3 || order[0] != "p3" || order[1] != "p1" || order[2] != "p2" {
+		t.Errorf("Expected turns in seat order, got %v", order)
+	}
+}
@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"time"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+// Decision is where a player stands in a simultaneous round
+type Decision struct {
+	UserId string             `json:"user_id"`
+	HandN0 pb.BlackjackHandN0 `json:"hand_n0"`
+	Done   bool               `json:"done"`
+	// Unix time in ms the hand being played times out at
+	Deadline int64 `json:"deadline,omitempty"`
+}
+
+type DecisionsUpdate struct {
+	Decisions []*Decision `json:"decisions"`
+}
+
+type decision struct {
+	done     bool
+	deadline time.Time
+}
+
+// StartDecisions opens a simultaneous round where every player decides at once against
+// the dealer upcard, players without a legal action are done already
+func (s *MatchState) StartDecisions(userIds []string, now time.Time, turn time.Duration) {
+	s.decisions = make(map[string]*decision, len(userIds))
+	for _, userId := range userIds {
+		s.decisions[userId] = &decision{
+			done:     len(s.GetLegalActionsByUserId(userId)) == 0,
+			deadline: now.Add(turn),
+		}
+	}
+}
+
+// IsSimultaneousRound reports whether players are deciding at once
+func (s *MatchState) IsSimultaneousRound() bool {
+	return s.decisions != nil
+}
+
+// IsInTurn reports whether userId may act now, its own decision in a simultaneous round
+// or the current turn otherwise
+func (s *MatchState) IsInTurn(userId string) bool {
+	if s.decisions != nil {
+		d, ok := s.decisions[userId]
+		return ok && !d.done
+	}
+	return s.currentTurn != "" && s.currentTurn == userId
+}
+
+// RenewDecision gives userId a new turn for the hand it plays now
+func (s *MatchState) RenewDecision(userId string, now time.Time, turn time.Duration) {
+	if d, ok := s.decisions[userId]; ok && !d.done {
+		d.deadline = now.Add(turn)
+	}
+}
+
+// FinishDecision closes the decision of userId once all its hands are played
+func (s *MatchState) FinishDecision(userId string) {
+	if d, ok := s.decisions[userId]; ok {
+		d.done = true
+	}
+}
+
+// ExpiredDecisions returns the players still deciding past their deadline, from first base
+func (s *MatchState) ExpiredDecisions(now time.Time) []string {
+	expired := make([]string, 0)
+	for _, presence := range s.GetPlayingPresences() {
+		userId := presence.GetUserId()
+		if d, ok := s.decisions[userId]; ok && !d.done && !d.deadline.After(now) {
+			expired = append(expired, userId)
+		}
+	}
+	return expired
+}
+
+// AllDecided reports whether every player of the simultaneous round stood or timed out
+func (s *MatchState) AllDecided() bool {
+	for _, d := range s.decisions {
+		if !d.done {
+			return false
+		}
+	}
+	return true
+}
+
+// GetDecisions returns the decisions of the simultaneous round from first base, nil out of one
+func (s *MatchState) GetDecisions() *DecisionsUpdate {
+	if s.decisions == nil {
+		return nil
+	}
+	update := &DecisionsUpdate{Decisions: make([]*Decision, 0, len(s.decisions))}
+	for _, presence := range s.GetPlayingPresences() {
+		userId := presence.GetUserId()
+		d, ok := s.decisions[userId]
+		if !ok {
+			continue
+		}
+		decision := &Decision{UserId: userId, HandN0: s.currentHand[userId], Done: d.done}
+		if !d.done {
+			decision.Deadline = d.deadline.UnixMilli()
+		}
+		update.Decisions = append(update.Decisions, decision)
+	}
+	return update
+}
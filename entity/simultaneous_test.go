@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func TestSimultaneousDecisions(t *testing.T) {
+	state := newTestTable("p1", "p2", "p3")
+	for _, id := range []string{"p1", "p2", "p3"} {
+		state.userBets[id] = &pb.BlackjackPlayerBet{UserId: id, First: 100}
+	}
+	state.SetupMatchPresence()
+	state.Init()
+	state.AddCards([]*pb.Card{
+		{Rank: pb.CardRank_RANK_9, Suit: pb.CardSuit_SUIT_SPADES},
+		{Rank: pb.CardRank_RANK_7, Suit: pb.CardSuit_SUIT_HEARTS},
+	}, "", pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
+	hands := map[string][]pb.CardRank{
+		"p1": {pb.CardRank_RANK_10, pb.CardRank_RANK_2},
+		"p2": {pb.CardRank_RANK_5, pb.CardRank_RANK_6},
+		// blackjack, nothing to decide
+		"p3": {pb.CardRank_RANK_A, pb.CardRank_RANK_K},
+	}
+	for id, ranks := range hands {
+		cards := make([]*pb.Card, 0, len(ranks))
+		for _, rank := range ranks {
+			cards = append(cards, &pb.Card{Rank: rank, Suit: pb.CardSuit_SUIT_CLUBS})
+		}
+		state.AddCards(cards, id, pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
+	}
+
+	if state.IsSimultaneousRound() || state.IsInTurn("p1") {
+		t.Fatal("Expected no decision before the round starts")
+	}
+	now := time.Now()
+	state.StartDecisions([]string{"p1", "p2", "p3"}, now, 10*time.Second)
+	if !state.IsInTurn("p1") || !state.IsInTurn("p2") || state.IsInTurn("p3") {
+		t.Errorf("Expected p1 and p2 to decide at once and p3 to be done")
+	}
+	if decisions := state.GetDecisions().Decisions; len(decisions) != 3 || decisions[0].UserId != "p1" || !decisions[2].Done {
+		t.Errorf("Unexpected decisions %+v", decisions)
+	}
+
+	state.FinishDecision("p1")
+	if state.IsInTurn("p1") || state.AllDecided() {
+		t.Error("Expected p1 done and p2 still deciding")
+	}
+	if expired := state.ExpiredDecisions(now.Add(5 * time.Second)); len(expired) != 0 {
+		t.Errorf("Expected no expired decision yet, got %v", expired)
+	}
+	state.RenewDecision("p2", now.Add(5*time.Second), 10*time.Second)
+	if expired := state.ExpiredDecisions(now.Add(12 * time.Second)); len(expired) != 0 {
+		t.Errorf("Expected the renewed decision to run, got %v", expired)
+	}
+	if expired := state.ExpiredDecisions(now.Add(15 * time.Second)); len(expired) != 1 || expired[0] != "p2" {
+		t.Errorf("Expected p2 to time out, got %v", expired)
+	}
+	state.FinishDecision("p2")
+	if !state.AllDecided() {
+		t.Error("Expected the round to close")
+	}
+
+	state.Init()
+	if state.IsSimultaneousRound() || state.GetDecisions() != nil {
+		t.Error("Expected the decisions to be reset for the next round")
+	}
+}
@@ -0,0 +1,153 @@
+package entity
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Sit-out error codes sent back to the requester
+const (
+	SitOutErrorNotSeated = "not_seated"
+	SitOutErrorBetPlaced = "bet_placed"
+)
+
+type SitOutRequest struct {
+	// true to sit out, false to sit back in
+	SitOut bool `json:"sit_out"`
+}
+
+type SitOutInfo struct {
+	UserId string `json:"user_id"`
+	Seat   int    `json:"seat"`
+	// Rounds dealt without the player
+	Rounds int `json:"rounds"`
+	// Rounds left before the seat is given up, -1 without a round limit
+	RoundsLeft int `json:"rounds_left"`
+	// Unix time the seat is given up at, 0 without a time limit
+	ExpireAt int64 `json:"expire_at"`
+}
+
+// SitOutUpdate lists the players sitting out, sent to the whole table
+type SitOutUpdate struct {
+	Players []*SitOutInfo `json:"players"`
+}
+
+type SitOutError struct {
+	Code string `json:"code"`
+}
+
+type sitOut struct {
+	since  time.Time
+	rounds int
+}
+
+// Default sit-out limits, the seat is given up at the first limit reached
+const (
+	DefaultSitOutMaxRounds   = 3
+	DefaultSitOutMaxDuration = 5 * time.Minute
+)
+
+var (
+	sitOutLimitsMu    sync.RWMutex
+	sitOutMaxRounds   = DefaultSitOutMaxRounds
+	sitOutMaxDuration = DefaultSitOutMaxDuration
+)
+
+// SetSitOutLimits sets how long a seat stays reserved for a player sitting out,
+// a zero limit is not applied, negative values restore the defaults
+func SetSitOutLimits(maxRounds int, maxDuration time.Duration) {
+	sitOutLimitsMu.Lock()
+	defer sitOutLimitsMu.Unlock()
+	if maxRounds < 0 {
+		maxRounds = DefaultSitOutMaxRounds
+	}
+	if maxDuration < 0 {
+		maxDuration = DefaultSitOutMaxDuration
+	}
+	sitOutMaxRounds = maxRounds
+	sitOutMaxDuration = maxDuration
+}
+
+func getSitOutLimits() (int, time.Duration) {
+	sitOutLimitsMu.RLock()
+	defer sitOutLimitsMu.RUnlock()
+	return sitOutMaxRounds, sitOutMaxDuration
+}
+
+// SitOut keeps the seat of userId reserved while the player skips rounds,
+// a player with a bet on the coming round has to play it
+func (s *MatchState) SitOut(userId string) string {
+	if _, seated := s.seatByUser[userId]; !seated || s.IsBot(userId) {
+		return SitOutErrorNotSeated
+	}
+	if s.IsAllowBet() && s.IsBet(userId) {
+		return SitOutErrorBetPlaced
+	}
+	if _, found := s.sitOuts[userId]; !found {
+		s.sitOuts[userId] = &sitOut{since: time.Now()}
+	}
+	s.ResetUserNotInteract(userId)
+	return ""
+}
+
+// SitIn brings a player sitting out back to the next round
+func (s *MatchState) SitIn(userId string) bool {
+	if _, found := s.sitOuts[userId]; !found {
+		return false
+	}
+	delete(s.sitOuts, userId)
+	s.ResetUserNotInteract(userId)
+	return true
+}
+
+func (s *MatchState) IsSittingOut(userId string) bool {
+	_, found := s.sitOuts[userId]
+	return found
+}
+
+// GetSitOuts returns the players sitting out from first base
+func (s *MatchState) GetSitOuts() *SitOutUpdate {
+	maxRounds, maxDuration := getSitOutLimits()
+	update := &SitOutUpdate{Players: make([]*SitOutInfo, 0, len(s.sitOuts))}
+	for userId, v := range s.sitOuts {
+		info := &SitOutInfo{UserId: userId, Seat: s.GetSeat(userId), Rounds: v.rounds, RoundsLeft: -1}
+		if maxRounds > 0 {
+			info.RoundsLeft = maxRounds - v.rounds
+		}
+		if maxDuration > 0 {
+			info.ExpireAt = v.since.Add(maxDuration).Unix()
+		}
+		update.Players = append(update.Players, info)
+	}
+	sort.Slice(update.Players, func(i, j int) bool {
+		return update.Players[i].Seat < update.Players[j].Seat
+	})
+	return update
+}
+
+// isSitOutExpired reports whether a player sat out the most rounds or the longest time allowed
+func (s *MatchState) isSitOutExpired(userId string, now time.Time) bool {
+	v, found := s.sitOuts[userId]
+	if !found {
+		return false
+	}
+	maxRounds, maxDuration := getSitOutLimits()
+	return (maxRounds > 0 && v.rounds >= maxRounds) ||
+		(maxDuration > 0 && now.Sub(v.since) >= maxDuration)
+}
+
+// countSitOutRound counts a round dealt without the players sitting out
+func (s *MatchState) countSitOutRound() {
+	for _, v := range s.sitOuts {
+		v.rounds++
+	}
+}
+
+// forgetAfk drops the sit-out and idle state of a player leaving the seat
+func (s *MatchState) forgetAfk(userId string) {
+	delete(s.sitOuts, userId)
+	delete(s.afkRounds, userId)
+	delete(s.afkActive, userId)
+	delete(s.disconnected, userId)
+}
@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func TestSitOut(t *testing.T) {
+	state := newTestTable("p1", "p2")
+	if code := state.SitOut("p3"); code != SitOutErrorNotSeated {
+		t.Errorf("Expected unseated user to be refused, got %q", code)
+	}
+
+	state.SetAllowBet(true)
+	state.userBets["p1"] = &pb.BlackjackPlayerBet{UserId: "p1", First: 100}
+	if code := state.SitOut("p1"); code != SitOutErrorBetPlaced {
+		t.Errorf("Expected a bet on the coming round to be played, got %q", code)
+	}
+	if code := state.SitOut("p2"); code != "" {
+		t.Fatalf("Unexpected error %q", code)
+	}
+	state.userBets["p2"] = &pb.BlackjackPlayerBet{UserId: "p2", First: 100}
+
+	state.SetupMatchPresence()
+	if _, found := state.PlayingPresences.Get("p2"); found {
+		t.Errorf("Expected players sitting out to be skipped")
+	}
+	if info := state.GetSitOuts().Players; len(info) != 1 || info[0].Rounds != 1 || info[0].Seat != 2 {
+		t.Errorf("Unexpected sit-outs %+v", info)
+	}
+
+	if !state.SitIn("p2") || state.IsSittingOut("p2") {
+		t.Errorf("Expected p2 to sit back in")
+	}
+	if state.SitIn("p2") {
+		t.Errorf("Expected sit in to be a no-op")
+	}
+}
+
+func TestSitOutExpiry(t *testing.T) {
+	SetSitOutLimits(2, time.Minute)
+	defer SetSitOutLimits(-1, -1)
+
+	state := newTestTable("p1")
+	state.SitOut("p1")
+	state.SetupMatchPresence()
+	state.CountAfkRound()
+	if state.GetAfkRounds("p1") != 0 || len(state.CheckAfk(time.Now())) != 0 {
+		t.Errorf("Expected a player sitting out not to be idle")
+	}
+
+	state.SetupMatchPresence()
+	if d := state.CheckAfk(time.Now()); len(d) != 1 || d[0].UserId != "p1" || d[0].Reason != KickReasonSitOutExpired {
+		t.Errorf("Expected the seat to be given up after 2 rounds, got %+v", d)
+	}
+
+	state.sitOuts["p1"] = &sitOut{since: time.Now().Add(-2 * time.Minute)}
+	if !state.isSitOutExpired("p1", time.Now()) {
+		t.Errorf("Expected the seat to be given up after the time limit")
+	}
+
+	state.RemovePresences(&FakePrecense{UserId: "p1"})
+	if state.IsSittingOut("p1") {
+		t.Errorf("Expected sit-out to end with the presence")
+	}
+}
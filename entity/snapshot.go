@@ -0,0 +1,129 @@
+package entity
+
+import (
+	"sort"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+// TableSnapshotVersion is bumped whenever a field changes meaning or is removed
+const TableSnapshotVersion = 1
+
+// TableSnapshot is everything a client needs to redraw the table after a reconnect
+type TableSnapshot struct {
+	Version   int          `json:"version"`
+	MatchId   string       `json:"match_id"`
+	MarkUnit  int64        `json:"mark_unit"`
+	GameState pb.GameState `json:"game_state"`
+	// Seconds left in the current game state
+	Countdown float64 `json:"countdown"`
+	// Set while an operator paused or is closing the table
+	Status *TableStatus `json:"status,omitempty"`
+	// Main bet limits set by operators
+	Limits *TableLimits `json:"limits,omitempty"`
+
+	Seats   *SeatsUpdate  `json:"seats"`
+	SitOuts *SitOutUpdate `json:"sit_outs"`
+	// Players whose seat is held while they are disconnected
+	Disconnected []string `json:"disconnected"`
+
+	Bets []*pb.BlackjackPlayerBet `json:"bets"`
+	// Dealer hole card stays hidden until the dealer plays, then cards show as their step is sent
+	DealerHand *pb.BlackjackPlayerHand   `json:"dealer_hand,omitempty"`
+	Hands      []*pb.BlackjackPlayerHand `json:"hands"`
+	// Dealer steps sent so far, the following ones are broadcast to the receiver too
+	DealerSequence *DealerSequence `json:"dealer_sequence,omitempty"`
+
+	InTurn        string             `json:"in_turn"`
+	HandN0        pb.BlackjackHandN0 `json:"hand_n0"`
+	InsuranceTurn bool               `json:"insurance_turn"`
+	// Actions the receiver may take now, only set on its own turn
+	LegalActions []pb.BlackjackActionCode `json:"legal_actions"`
+	// Who is still deciding when all players decide at once
+	Decisions *DecisionsUpdate `json:"decisions,omitempty"`
+
+	// Chips in the receiver's wallet, filled by the sender
+	Wallet   int64      `json:"wallet"`
+	PropBets []*PropBet `json:"prop_bets"`
+
+	// Result of the round being rewarded and the settlement of the last round played
+	Finish         *pb.BlackjackUpdateFinish `json:"finish,omitempty"`
+	LastSettlement *pb.BalanceResult         `json:"last_settlement,omitempty"`
+}
+
+// TableSnapshot builds the table as seen by userId, a seated player or a spectator
+func (s *MatchState) TableSnapshot(userId string) *TableSnapshot {
+	snapshot := &TableSnapshot{
+		Version:        TableSnapshotVersion,
+		MatchId:        s.Label.MatchId,
+		MarkUnit:       int64(s.Label.MarkUnit),
+		GameState:      s.GetGameState(),
+		Seats:          s.GetSeats(),
+		SitOuts:        s.GetSitOuts(),
+		Disconnected:   make([]string, 0, len(s.disconnected)),
+		Bets:           s.GetPlayersBet(),
+		Hands:          make([]*pb.BlackjackPlayerHand, 0),
+		LegalActions:   make([]pb.BlackjackActionCode, 0),
+		PropBets:       s.GetPropBets(userId),
+		LastSettlement: s.lastSettlement,
+		Status:         s.GetTableStatus(),
+		Limits:         s.GetTableLimits(),
+	}
+	for userId := range s.disconnected {
+		snapshot.Disconnected = append(snapshot.Disconnected, userId)
+	}
+	sort.Strings(snapshot.Disconnected)
+	if remain := s.GetRemainCountDown(); remain > 0 {
+		snapshot.Countdown = remain
+	}
+	sort.Slice(snapshot.Bets, func(i, j int) bool {
+		return s.seatOrder(snapshot.Bets[i].UserId) < s.seatOrder(snapshot.Bets[j].UserId)
+	})
+	switch snapshot.GameState {
+	case pb.GameState_GAME_STATE_PLAY, pb.GameState_GAME_STATE_REWARD:
+	default:
+		return snapshot
+	}
+
+	sequenceDone := true
+	if sequence := s.dealerSequence; sequence != nil {
+		sequenceDone = sequence.Shown == len(sequence.Steps)
+		snapshot.DealerSequence = &DealerSequence{Steps: sequence.Steps[:sequence.Shown], Shown: sequence.Shown}
+	}
+	if len(s.dealerHand.first) > 0 {
+		snapshot.DealerHand = s.GetDealerHand()
+		if snapshot.GameState == pb.GameState_GAME_STATE_PLAY || !sequenceDone {
+			snapshot.DealerHand = &pb.BlackjackPlayerHand{
+				First: &pb.BlackjackHand{Cards: s.shownDealerCards()},
+			}
+		}
+	}
+	for _, presence := range s.GetPlayingPresences() {
+		if s.PlayerHand(presence.GetUserId()) != nil {
+			snapshot.Hands = append(snapshot.Hands, s.GetPlayerHand(presence.GetUserId()))
+		}
+	}
+	if snapshot.GameState == pb.GameState_GAME_STATE_REWARD {
+		if sequenceDone {
+			snapshot.Finish = s.GetUpdateFinish()
+		}
+		return snapshot
+	}
+	snapshot.InTurn = s.GetCurrentTurn()
+	snapshot.HandN0 = s.GetCurrentHandN0(snapshot.InTurn)
+	snapshot.InsuranceTurn = s.IsAllowInsurance()
+	snapshot.Decisions = s.GetDecisions()
+	if s.IsInTurn(userId) && s.PlayerHand(userId) != nil {
+		snapshot.HandN0 = s.GetCurrentHandN0(userId)
+		snapshot.LegalActions = s.GetLegalActionsByUserId(userId)
+	}
+	return snapshot
+}
+
+// override, the settlement is kept for snapshots after the reward phase ends
+func (s *MatchState) SetBalanceResult(u *pb.BalanceResult) {
+	s.baseMatchState.SetBalanceResult(u)
+	if u != nil {
+		s.lastSettlement = u
+	}
+}
@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"testing"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func TestTableSnapshotPlay(t *testing.T) {
+	state := NewMatchState(&pb.Match{MatchId: "m1", MarkUnit: 100})
+	for _, id := range []string{"p1", "p2"} {
+		state.Presences.Put(id, &FakePrecense{UserId: id})
+		state.assignSeat(id)
+		state.userBets[id] = &pb.BlackjackPlayerBet{UserId: id, First: 100}
+	}
+	state.SetupMatchPresence()
+	state.SetGameState(pb.GameState_GAME_STATE_PLAY)
+	state.AddCards([]*pb.Card{
+		{Rank: pb.CardRank_RANK_9, Suit: pb.CardSuit_SUIT_SPADES},
+		{Rank: pb.CardRank_RANK_K, Suit: pb.CardSuit_SUIT_HEARTS},
+	}, "", pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
+	for _, id := range []string{"p1", "p2"} {
+		state.AddCards([]*pb.Card{
+			{Rank: pb.CardRank_RANK_5, Suit: pb.CardSuit_SUIT_CLUBS},
+			{Rank: pb.CardRank_RANK_6, Suit: pb.CardSuit_SUIT_DIAMONDS},
+		}, id, pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
+	}
+	state.SetCurrentTurn("p1")
+
+	snapshot := state.TableSnapshot("p1")
+	if snapshot.Version != TableSnapshotVersion || snapshot.MatchId != "m1" {
+		t.Errorf("Unexpected header %+v", snapshot)
+	}
+	if len(snapshot.Bets) != 2 || snapshot.Bets[0].UserId != "p1" {
+		t.Errorf("Expected bets in seat order, got %+v", snapshot.Bets)
+	}
+	if len(snapshot.Hands) != 2 {
+		t.Errorf("Expected both hands, got %d", len(snapshot.Hands))
+	}
+	if cards := snapshot.DealerHand.First.Cards; len(cards) != 2 || cards[1].Rank != pb.CardRank_RANK_UNSPECIFIED {
+		t.Errorf("Expected the dealer hole card to be hidden, got %+v", cards)
+	}
+	if snapshot.InTurn != "p1" || len(snapshot.LegalActions) == 0 {
+		t.Errorf("Expected legal actions on p1's turn, got %+v", snapshot.LegalActions)
+	}
+	if other := state.TableSnapshot("p2"); len(other.LegalActions) != 0 {
+		t.Errorf("Expected no legal actions out of turn, got %+v", other.LegalActions)
+	}
+}
+
+func TestTableSnapshotSettlement(t *testing.T) {
+	state := newTestTable()
+	state.SetGameState(pb.GameState_GAME_STATE_PREPARING)
+	if snapshot := state.TableSnapshot("p1"); snapshot.DealerHand != nil || snapshot.LastSettlement != nil {
+		t.Errorf("Expected an empty table before the first round, got %+v", snapshot)
+	}
+
+	result := &pb.BalanceResult{}
+	state.SetBalanceResult(result)
+	state.ResetBalanceResult()
+	if snapshot := state.TableSnapshot("p1"); snapshot.LastSettlement != result {
+		t.Errorf("Expected the last settlement to outlive the reward phase")
+	}
+}
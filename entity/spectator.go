@@ -0,0 +1,171 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/emirpasic/gods/maps/linkedhashmap"
+	"github.com/heroiclabs/nakama-common/runtime"
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+// MaxSpectators is the number of presences that can watch a table without a seat
+const MaxSpectators = 20
+
+// PendingSpectatorTimeout frees the place of a spectator whose accepted join never completed
+const PendingSpectatorTimeout = 30 * time.Second
+
+type SeatQueueRequest struct {
+	Join bool `json:"join"`
+}
+
+// SeatQueueUpdate is the seat queue in order, head first
+type SeatQueueUpdate struct {
+	Queue      []string `json:"queue"`
+	Spectators int      `json:"spectators"`
+}
+
+type SeatPromoted struct {
+	UserId string `json:"user_id"`
+}
+
+// IsSpectatorOpCode reports whether a spectator may send opCode
+func IsSpectatorOpCode(opCode int64) bool {
+	switch opCode {
+	case int64(pb.OpCodeRequest_OPCODE_REQUEST_INFO_TABLE),
+		int64(pb.OpCodeRequest_OPCODE_REQUEST_SYNC_TABLE),
+		int64(pb.OpCodeRequest_OPCODE_REQUEST_LEAVE_GAME),
+		OpCodeRequestChat, OpCodeRequestEmote, OpCodeRequestMute, OpCodeRequestReport,
+		OpCodeRequestPropBet, OpCodeRequestSeatQueue, OpCodeRequestSitSeat:
+		return true
+	}
+	return false
+}
+
+// HasFreeSeat reports whether a new player can take a seat
+func (s *MatchState) HasFreeSeat() bool {
+	return s.Presences.Size()+s.JoinsInProgress < s.MaxPresences
+}
+
+// CanSpectate reports whether one more spectator can watch the table
+func (s *MatchState) CanSpectate() bool {
+	s.expirePendingSpectators(time.Now())
+	return s.spectators.Size()+len(s.pendingSpectators) < MaxSpectators
+}
+
+// AddPendingSpectator marks a user accepted by MatchJoinAttempt to join as spectator
+func (s *MatchState) AddPendingSpectator(userId string) {
+	s.pendingSpectators[userId] = time.Now()
+}
+
+func (s *MatchState) IsPendingSpectator(userId string) bool {
+	_, found := s.pendingSpectators[userId]
+	return found
+}
+
+// RemovePendingSpectator forgets an accepted spectator join, the user attempts another one
+func (s *MatchState) RemovePendingSpectator(userId string) {
+	delete(s.pendingSpectators, userId)
+}
+
+// expirePendingSpectators drops the spectator joins accepted longer than the timeout ago
+func (s *MatchState) expirePendingSpectators(now time.Time) {
+	for userId, at := range s.pendingSpectators {
+		if now.Sub(at) > PendingSpectatorTimeout {
+			delete(s.pendingSpectators, userId)
+		}
+	}
+}
+
+// AddSpectator lets presence watch the table without a seat
+func (s *MatchState) AddSpectator(presence runtime.Presence) {
+	delete(s.pendingSpectators, presence.GetUserId())
+	s.spectators.Put(presence.GetUserId(), presence)
+}
+
+// RemoveSpectator drops a spectator and its place in the seat queue,
+// returns false when userId is not a spectator
+func (s *MatchState) RemoveSpectator(userId string) bool {
+	delete(s.pendingSpectators, userId)
+	if _, found := s.spectators.Get(userId); !found {
+		return false
+	}
+	s.spectators.Remove(userId)
+	s.LeaveSeatQueue(userId)
+	return true
+}
+
+func (s *MatchState) IsSpectator(userId string) bool {
+	_, found := s.spectators.Get(userId)
+	return found
+}
+
+func (s *MatchState) GetSpectators() []runtime.Presence {
+	p := make([]runtime.Presence, 0, s.spectators.Size())
+	s.spectators.Each(func(key, value interface{}) { p = append(p, value.(runtime.Presence)) })
+	return p
+}
+
+// GetPresenceOrSpectator returns the presence of a seated user or a spectator
+func (s *MatchState) GetPresenceOrSpectator(userId string) runtime.Presence {
+	if presence := s.GetPresence(userId); presence != nil {
+		return presence
+	}
+	if v, found := s.spectators.Get(userId); found {
+		return v.(runtime.Presence)
+	}
+	return nil
+}
+
+// JoinSeatQueue puts a spectator at the end of the seat queue
+func (s *MatchState) JoinSeatQueue(userId string) bool {
+	if !s.IsSpectator(userId) {
+		return false
+	}
+	for _, v := range s.seatQueue {
+		if v == userId {
+			return false
+		}
+	}
+	s.seatQueue = append(s.seatQueue, userId)
+	return true
+}
+
+// LeaveSeatQueue removes userId from the seat queue
+func (s *MatchState) LeaveSeatQueue(userId string) bool {
+	for i, v := range s.seatQueue {
+		if v == userId {
+			s.seatQueue = append(s.seatQueue[:i], s.seatQueue[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// GetSeatQueue returns the seat queue, head first
+func (s *MatchState) GetSeatQueue() []string {
+	queue := make([]string, len(s.seatQueue))
+	copy(queue, s.seatQueue)
+	return queue
+}
+
+// PopSeatQueue removes the head of the queue from the spectators when a seat is free,
+// the caller seats the returned presence
+func (s *MatchState) PopSeatQueue() runtime.Presence {
+	if len(s.seatQueue) == 0 || !s.HasFreeSeat() {
+		return nil
+	}
+	userId := s.seatQueue[0]
+	s.seatQueue = s.seatQueue[1:]
+	v, found := s.spectators.Get(userId)
+	if !found {
+		return nil
+	}
+	s.spectators.Remove(userId)
+	return v.(runtime.Presence)
+}
+
+func (s *MatchState) newSpectatorState() {
+	s.spectators = linkedhashmap.New()
+	s.pendingSpectators = make(map[string]time.Time)
+	s.seatQueue = make([]string, 0)
+}
@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func TestSeatQueue(t *testing.T) {
+	state := newTestTable("p1", "p2")
+	state.MaxPresences = 2
+	if state.HasFreeSeat() {
+		t.Fatalf("Expected table to be full")
+	}
+
+	state.AddPendingSpectator("s1")
+	if !state.IsPendingSpectator("s1") {
+		t.Errorf("Expected pending spectator")
+	}
+	state.AddSpectator(&FakePrecense{UserId: "s1"})
+	state.AddSpectator(&FakePrecense{UserId: "s2"})
+	if state.IsPendingSpectator("s1") || !state.IsSpectator("s1") {
+		t.Errorf("Expected s1 to be a spectator")
+	}
+
+	if state.JoinSeatQueue("p1") {
+		t.Errorf("Expected seated players to be refused from the queue")
+	}
+	state.JoinSeatQueue("s2")
+	state.JoinSeatQueue("s1")
+	if state.JoinSeatQueue("s1") {
+		t.Errorf("Expected a spectator to queue once")
+	}
+	if queue := state.GetSeatQueue(); len(queue) != 2 || queue[0] != "s2" {
+		t.Errorf("Unexpected queue %v", queue)
+	}
+	if !state.BotFillInput().HumanWaiting {
+		t.Errorf("Expected queued spectators to count as waiting humans")
+	}
+	if state.PopSeatQueue() != nil {
+		t.Errorf("Expected no promotion while the table is full")
+	}
+
+	state.Presences.Remove("p1")
+	presence := state.PopSeatQueue()
+	if presence == nil || presence.GetUserId() != "s2" {
+		t.Fatalf("Expected queue head to be promoted, got %v", presence)
+	}
+	if state.IsSpectator("s2") {
+		t.Errorf("Expected promoted user to stop spectating")
+	}
+
+	if !state.RemoveSpectator("s1") || len(state.GetSeatQueue()) != 0 {
+		t.Errorf("Expected leaving spectator to leave the queue")
+	}
+	if state.RemoveSpectator("p2") {
+		t.Errorf("Expected seated player not to be removed as spectator")
+	}
+}
+
+func TestIsSpectatorOpCode(t *testing.T) {
+	if IsSpectatorOpCode(int64(pb.OpCodeRequest_OPCODE_REQUEST_BET)) {
+		t.Errorf("Expected spectators not to bet")
+	}
+	for _, opCode := range []int64{OpCodeRequestChat, OpCodeRequestPropBet, OpCodeRequestSeatQueue,
+		int64(pb.OpCodeRequest_OPCODE_REQUEST_SYNC_TABLE)} {
+		if !IsSpectatorOpCode(opCode) {
+			t.Errorf("Expected spectators to send opcode %d", opCode)
+		}
+	}
+}
+
+func TestPendingSpectatorExpires(t *testing.T) {
+	state := newTestTable()
+	for i := 0; i < MaxSpectators; i++ {
+		state.AddPendingSpectator(fmt.Sprintf("s%d", i))
+	}
+	if state.CanSpectate() {
+		t.Fatalf("Expected pending joins to take the spectator places")
+	}
+	state.pendingSpectators["s0"] = time.Now().Add(-PendingSpectatorTimeout - time.Second)
+	if !state.CanSpectate() || state.IsPendingSpectator("s0") {
+		t.Errorf("Expected a join that never completed to free its place")
+	}
+	state.RemovePendingSpectator("s1")
+	if state.IsPendingSpectator("s1") {
+		t.Errorf("Expected a new join attempt to drop the pending spectator")
+	}
+}
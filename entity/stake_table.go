@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"sort"
+	"sync"
+)
+
+// stakeTable holds a value per stake level, keyed by the lowest MarkUnit the value applies to
+type stakeTable[T any] struct {
+	mu       sync.RWMutex
+	fallback T
+	values   map[int64]T
+}
+
+func newStakeTable[T any](fallback T) *stakeTable[T] {
+	return &stakeTable[T]{
+		fallback: fallback,
+		values:   map[int64]T{0: fallback},
+	}
+}
+
+// Set replaces the values of every stake level, nil restores the fallback
+func (t *stakeTable[T]) Set(values map[int64]T) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if values == nil {
+		values = map[int64]T{0: t.fallback}
+	}
+	t.values = values
+}
+
+// For returns the value of the highest stake level not above markUnit
+func (t *stakeTable[T]) For(markUnit int64) T {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	stakes := make([]int64, 0, len(t.values))
+	for stake := range t.values {
+		stakes = append(stakes, stake)
+	}
+	sort.Slice(stakes, func(i, j int) bool { return stakes[i] > stakes[j] })
+	for _, stake := range stakes {
+		if stake <= markUnit {
+			return t.values[stake]
+		}
+	}
+	return t.fallback
+}
@@ -0,0 +1,319 @@
+package entity
+
+import (
+	"math/rand"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+const (
+	ChatMaxLength     = 200
+	ChatHistorySize   = 50
+	ChatRateWindow    = 10 * time.Second
+	ChatRateMaxChat   = 5
+	ChatRateMaxEmote  = 8
+	ChatReportContext = 10
+)
+
+// Chat error codes sent back to the sender
+const (
+	ChatErrorRateLimited  = "rate_limited"
+	ChatErrorEmpty        = "empty"
+	ChatErrorTooLong      = "too_long"
+	ChatErrorUnknownEmote = "unknown_emote"
+	ChatErrorUnknownUser  = "unknown_user"
+)
+
+// Emotes a client can send
+var ChatEmotes = []string{"like", "clap", "laugh", "wow", "cry", "angry", "facepalm", "gg"}
+
+type ChatRequest struct {
+	Text string `json:"text"`
+}
+
+type EmoteRequest struct {
+	Emote string `json:"emote"`
+}
+
+type MuteRequest struct {
+	UserId string `json:"user_id"`
+	Mute   bool   `json:"mute"`
+}
+
+type ReportRequest struct {
+	UserId string `json:"user_id"`
+	Reason string `json:"reason"`
+}
+
+// ChatMessage is a chat line or emote relayed to the table
+type ChatMessage struct {
+	UserId string `json:"user_id"`
+	Text   string `json:"text,omitempty"`
+	Emote  string `json:"emote,omitempty"`
+	Time   int64  `json:"time"`
+}
+
+type ChatError struct {
+	Code string `json:"code"`
+}
+
+// IsChatEmote reports whether emote is one clients can send
+func IsChatEmote(emote string) bool {
+	for _, v := range ChatEmotes {
+		if v == emote {
+			return true
+		}
+	}
+	return false
+}
+
+// ChatRateLimiter allows at most max events per user in a sliding window
+type ChatRateLimiter struct {
+	window time.Duration
+	max    int
+	sent   map[string][]time.Time
+	now    func() time.Time
+}
+
+func NewChatRateLimiter(window time.Duration, max int) *ChatRateLimiter {
+	return &ChatRateLimiter{
+		window: window,
+		max:    max,
+		sent:   make(map[string][]time.Time),
+		now:    time.Now,
+	}
+}
+
+// Allow records an event of userId and reports whether it is within the limit
+func (l *ChatRateLimiter) Allow(userId string) bool {
+	now := l.now()
+	sent := l.sent[userId]
+	kept := sent[:0]
+	for _, t := range sent {
+		if now.Sub(t) < l.window {
+			kept = append(kept, t)
+		}
+	}
+	if len(kept) >= l.max {
+		l.sent[userId] = kept
+		return false
+	}
+	l.sent[userId] = append(kept, now)
+	return true
+}
+
+// Forget drops the history of a user leaving the table
+func (l *ChatRateLimiter) Forget(userId string) {
+	delete(l.sent, userId)
+}
+
+// DefaultChatBannedWords is the word filter used when no config is provided
+var DefaultChatBannedWords = []string{"fuck", "shit", "bitch", "cunt", "dick", "asshole"}
+
+var (
+	chatFilterMu sync.RWMutex
+	chatFilter   = compileChatFilter(DefaultChatBannedWords)
+)
+
+func compileChatFilter(words []string) *regexp.Regexp {
+	quoted := make([]string, 0, len(words))
+	for _, w := range words {
+		if w = strings.TrimSpace(w); w != "" {
+			quoted = append(quoted, regexp.QuoteMeta(w))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`(?i)\b(` + strings.Join(quoted, "|") + `)\b`)
+}
+
+// SetChatBannedWords replaces the word filter, nil restores the default
+func SetChatBannedWords(words []string) {
+	if words == nil {
+		words = DefaultChatBannedWords
+	}
+	filter := compileChatFilter(words)
+	chatFilterMu.Lock()
+	defer chatFilterMu.Unlock()
+	chatFilter = filter
+}
+
+// FilterChatText masks banned words with asterisks
+func FilterChatText(text string) string {
+	chatFilterMu.RLock()
+	filter := chatFilter
+	chatFilterMu.RUnlock()
+	if filter == nil {
+		return text
+	}
+	return filter.ReplaceAllStringFunc(text, func(w string) string {
+		return strings.Repeat("*", utf8.RuneCountInString(w))
+	})
+}
+
+// TableChat keeps the chat state of a table: rate limits, mutes and recent history
+type TableChat struct {
+	chatLimiter  *ChatRateLimiter
+	emoteLimiter *ChatRateLimiter
+	// muter -> muted users
+	mutes   map[string]map[string]bool
+	history []ChatMessage
+}
+
+func NewTableChat() *TableChat {
+	return &TableChat{
+		chatLimiter:  NewChatRateLimiter(ChatRateWindow, ChatRateMaxChat),
+		emoteLimiter: NewChatRateLimiter(ChatRateWindow, ChatRateMaxEmote),
+		mutes:        make(map[string]map[string]bool),
+	}
+}
+
+// NewChat validates and filters a chat line, returns an error code when it is rejected
+func (c *TableChat) NewChat(userId, text string) (*ChatMessage, string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, ChatErrorEmpty
+	}
+	if utf8.RuneCountInString(text) > ChatMaxLength {
+		return nil, ChatErrorTooLong
+	}
+	if !c.chatLimiter.Allow(userId) {
+		return nil, ChatErrorRateLimited
+	}
+	msg := ChatMessage{UserId: userId, Text: FilterChatText(text), Time: time.Now().Unix()}
+	c.addHistory(msg)
+	return &msg, ""
+}
+
+// NewEmote validates an emote, returns an error code when it is rejected
+func (c *TableChat) NewEmote(userId, emote string) (*ChatMessage, string) {
+	if !IsChatEmote(emote) {
+		return nil, ChatErrorUnknownEmote
+	}
+	if !c.emoteLimiter.Allow(userId) {
+		return nil, ChatErrorRateLimited
+	}
+	msg := ChatMessage{UserId: userId, Emote: emote, Time: time.Now().Unix()}
+	c.addHistory(msg)
+	return &msg, ""
+}
+
+func (c *TableChat) addHistory(msg ChatMessage) {
+	c.history = append(c.history, msg)
+	if len(c.history) > ChatHistorySize {
+		c.history = c.history[len(c.history)-ChatHistorySize:]
+	}
+}
+
+// History returns the last chat lines of userId, oldest first
+func (c *TableChat) History(userId string, limit int) []ChatMessage {
+	res := make([]ChatMessage, 0)
+	for i := len(c.history) - 1; i >= 0 && len(res) < limit; i-- {
+		if c.history[i].UserId == userId {
+			res = append([]ChatMessage{c.history[i]}, res...)
+		}
+	}
+	return res
+}
+
+// SetMute hides (or shows again) the messages of muted to muter
+func (c *TableChat) SetMute(muter, muted string, mute bool) {
+	if !mute {
+		delete(c.mutes[muter], muted)
+		return
+	}
+	if c.mutes[muter] == nil {
+		c.mutes[muter] = make(map[string]bool)
+	}
+	c.mutes[muter][muted] = true
+}
+
+// IsMuted reports whether muter muted sender
+func (c *TableChat) IsMuted(muter, sender string) bool {
+	return c.mutes[muter][sender]
+}
+
+// Forget drops the chat state of a user leaving the table
+func (c *TableChat) Forget(userId string) {
+	c.chatLimiter.Forget(userId)
+	c.emoteLimiter.Forget(userId)
+	delete(c.mutes, userId)
+	for _, muted := range c.mutes {
+		delete(muted, userId)
+	}
+}
+
+// Chat returns the chat state of the table
+func (s *MatchState) Chat() *TableChat {
+	return s.chat
+}
+
+// Bot reactions to the end of a round
+const (
+	// BotReactBigWinUnits is the net win in MarkUnit that counts as a big win
+	BotReactBigWinUnits = 10
+	// BotReactMaxPerRound caps the reactions so bots don't flood the chat
+	BotReactMaxPerRound = 2
+)
+
+// BotReactChance is the probability a bot reacts to an event
+var BotReactChance = 0.5
+
+var botReactionsBigWin = []ChatMessage{
+	{Emote: "clap"}, {Emote: "wow"}, {Text: "nice hand!"}, {Text: "gg"}, {Text: "wow, lucky you"},
+}
+
+var botReactionsBust = []ChatMessage{
+	{Emote: "facepalm"}, {Emote: "cry"}, {Text: "ouch"}, {Text: "unlucky"}, {Text: "so close"},
+}
+
+// BotReactions returns canned bot reactions to big wins and busts of the finished round
+func (s *MatchState) BotReactions(balanceResult *pb.BalanceResult) []ChatMessage {
+	if balanceResult == nil || len(s.Bots) == 0 {
+		return nil
+	}
+	bigWin := int64(s.Label.MarkUnit) * BotReactBigWinUnits
+	reactions := make([]ChatMessage, 0)
+	for _, update := range balanceResult.Updates {
+		if len(reactions) >= BotReactMaxPerRound {
+			break
+		}
+		var pool []ChatMessage
+		switch {
+		case update.TotalChipInMatch >= bigWin && bigWin > 0:
+			pool = botReactionsBigWin
+		case s.isBusted(update.UserId):
+			pool = botReactionsBust
+		default:
+			continue
+		}
+		if rand.Float64() >= BotReactChance {
+			continue
+		}
+		bot := s.Bots[rand.Intn(len(s.Bots))]
+		msg := pool[rand.Intn(len(pool))]
+		msg.UserId = bot.GetUserId()
+		msg.Time = time.Now().Unix()
+		reactions = append(reactions, msg)
+	}
+	return reactions
+}
+
+func (s *MatchState) isBusted(userId string) bool {
+	hand := s.userHands[userId]
+	if hand == nil {
+		return false
+	}
+	for _, pos := range []pb.BlackjackHandN0{pb.BlackjackHandN0_BLACKJACK_HAND_1ST, pb.BlackjackHandN0_BLACKJACK_HAND_2ND} {
+		if _, _, handType := hand.Eval(pos); handType == pb.BlackjackHandType_BLACKJACK_HAND_TYPE_BUSTED {
+			return true
+		}
+	}
+	return false
+}
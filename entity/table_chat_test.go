@@ -0,0 +1,114 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nk-nigeria/cgp-common/bot"
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func TestChatRateLimiter(t *testing.T) {
+	now := time.Now()
+	limiter := NewChatRateLimiter(10*time.Second, 2)
+	limiter.now = func() time.Time { return now }
+
+	if !limiter.Allow("u1") || !limiter.Allow("u1") {
+		t.Fatalf("Expected first messages to be allowed")
+	}
+	if limiter.Allow("u1") {
+		t.Errorf("Expected third message in the window to be limited")
+	}
+	if !limiter.Allow("u2") {
+		t.Errorf("Expected limits to be per user")
+	}
+	now = now.Add(11 * time.Second)
+	if !limiter.Allow("u1") {
+		t.Errorf("Expected message to be allowed once the window passed")
+	}
+}
+
+func TestFilterChatText(t *testing.T) {
+	SetChatBannedWords([]string{"darn", "heck"})
+	defer SetChatBannedWords(nil)
+
+	testCases := []struct {
+		text     string
+		expected string
+	}{
+		{"well darn it", "well **** it"},
+		{"HECK no", "**** no"},
+		{"darnest day", "darnest day"},
+		{"hello", "hello"},
+	}
+	for _, tc := range testCases {
+		if got := FilterChatText(tc.text); got != tc.expected {
+			t.Errorf("Expected %q, got %q", tc.expected, got)
+		}
+	}
+}
+
+func TestTableChat(t *testing.T) {
+	chat := NewTableChat()
+	if _, code := chat.NewChat("u1", "   "); code != ChatErrorEmpty {
+		t.Errorf("Expected empty error, got %q", code)
+	}
+	if _, code := chat.NewEmote("u1", "dance"); code != ChatErrorUnknownEmote {
+		t.Errorf("Expected unknown emote error, got %q", code)
+	}
+	msg, code := chat.NewChat("u1", "hi all")
+	if code != "" || msg.Text != "hi all" || msg.UserId != "u1" {
+		t.Errorf("Unexpected chat %+v, code %q", msg, code)
+	}
+	chat.NewEmote("u2", "clap")
+	if history := chat.History("u1", 10); len(history) != 1 || history[0].Text != "hi all" {
+		t.Errorf("Unexpected history %+v", history)
+	}
+
+	chat.SetMute("u2", "u1", true)
+	if !chat.IsMuted("u2", "u1") || chat.IsMuted("u1", "u2") {
+		t.Errorf("Expected mute to be one way")
+	}
+	chat.SetMute("u2", "u1", false)
+	if chat.IsMuted("u2", "u1") {
+		t.Errorf("Expected unmute")
+	}
+	chat.SetMute("u2", "u1", true)
+	chat.Forget("u1")
+	if chat.IsMuted("u2", "u1") {
+		t.Errorf("Expected mutes of a leaving user to be dropped")
+	}
+}
+
+func TestBotReactionsWithoutBots(t *testing.T) {
+	state := NewMatchState(&pb.Match{MarkUnit: 100})
+	reactions := state.BotReactions(&pb.BalanceResult{Updates: []*pb.BalanceUpdate{
+		{UserId: "human", TotalChipInMatch: 5000},
+	}})
+	if len(reactions) != 0 {
+		t.Errorf("Expected no reactions without bots, got %+v", reactions)
+	}
+}
+
+func TestBotReactionsBigWin(t *testing.T) {
+	chance := BotReactChance
+	BotReactChance = 1
+	defer func() { BotReactChance = chance }()
+
+	state := NewMatchState(&pb.Match{MarkUnit: 100})
+	state.Bots = append(state.Bots, &bot.BotPresence{})
+	reactions := state.BotReactions(&pb.BalanceResult{Updates: []*pb.BalanceUpdate{
+		{UserId: "h1", TotalChipInMatch: 5000},
+		{UserId: "h2", TotalChipInMatch: 100},
+		{UserId: "h3", TotalChipInMatch: 2000},
+		{UserId: "h4", TotalChipInMatch: 3000},
+	}})
+	if len(reactions) != BotReactMaxPerRound {
+		t.Fatalf("Expected %d reactions, got %+v", BotReactMaxPerRound, reactions)
+	}
+	for _, r := range reactions {
+		if r.Text == "" && !IsChatEmote(r.Emote) {
+			t.Errorf("Unexpected reaction %+v", r)
+		}
+	}
+}
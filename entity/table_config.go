@@ -0,0 +1,169 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// What is done for a player whose turn runs out
+const (
+	TimeoutStand         = AutoPlayStand
+	TimeoutBasicStrategy = AutoPlayBasicStrategy
+	TimeoutForfeit       = "forfeit"
+)
+
+// What is done for a player who lets the insurance round run out
+const (
+	InsuranceTimeoutDecline = "decline"
+	InsuranceTimeoutInsure  = "insure"
+)
+
+// TableConfig declares the turn timers of a stake, the time bank of its players,
+// what is done when a turn runs out and how the shoe is dealt
+type TableConfig struct {
+	InsuranceSeconds int `json:"insurance_seconds"`
+	TurnSeconds      int `json:"turn_seconds"`
+	// Seconds a player starts with in the time bank, also its cap, 0 disables the bank
+	TimeBankSeconds int `json:"time_bank_seconds"`
+	// Seconds added to the bank every TimeBankTopUpRounds rounds played
+	TimeBankTopUpSeconds int `json:"time_bank_top_up_seconds"`
+	TimeBankTopUpRounds  int `json:"time_bank_top_up_rounds"`
+	// Most seconds drawn from the bank when a turn runs out
+	TimeBankExtensionSeconds int `json:"time_bank_extension_seconds"`
+	// TimeoutAction is stand, basic_strategy or forfeit, empty is stand
+	TimeoutAction string `json:"timeout_action"`
+	// InsuranceTimeoutAction is decline or insure, empty is decline
+	InsuranceTimeoutAction string `json:"insurance_timeout_action"`
+	// PersistentShoe keeps the shoe over several rounds on this stake even when the module
+	// deals a new shoe every round
+	PersistentShoe bool `json:"persistent_shoe"`
+	// Simultaneous lets all players decide their hands at once instead of turn by turn,
+	// each hand gets TurnSeconds
+	Simultaneous bool `json:"simultaneous"`
+}
+
+// DefaultTableConfig applies to every stake without its own config
+var DefaultTableConfig = TableConfig{
+	InsuranceSeconds:         5,
+	TurnSeconds:              10,
+	TimeBankSeconds:          30,
+	TimeBankTopUpSeconds:     10,
+	TimeBankTopUpRounds:      5,
+	TimeBankExtensionSeconds: 10,
+	TimeoutAction:            TimeoutStand,
+	InsuranceTimeoutAction:   InsuranceTimeoutDecline,
+}
+
+// tableConfigs holds the table config of each stake level
+var tableConfigs = newStakeTable(DefaultTableConfig)
+
+// ParseTableConfigs parses a per stake config like {"0":{"turn_seconds":10,"time_bank_seconds":30}}
+func ParseTableConfigs(raw string) (map[int64]TableConfig, error) {
+	configs := make(map[int64]TableConfig)
+	if err := json.Unmarshal([]byte(raw), &configs); err != nil {
+		return nil, err
+	}
+	for stake, c := range configs {
+		if stake < 0 || c.TimeBankSeconds < 0 || c.TimeBankTopUpSeconds < 0 ||
+			c.TimeBankTopUpRounds < 0 || c.TimeBankExtensionSeconds < 0 {
+			return nil, fmt.Errorf("negative value in table config of stake %d", stake)
+		}
+		if c.InsuranceSeconds <= 0 || c.TurnSeconds <= 0 {
+			return nil, fmt.Errorf("turn timers of stake %d must be positive", stake)
+		}
+		switch c.TimeoutAction {
+		case "":
+			c.TimeoutAction = TimeoutStand
+		case TimeoutStand, TimeoutBasicStrategy, TimeoutForfeit:
+		default:
+			return nil, fmt.Errorf("unknown timeout action %q of stake %d", c.TimeoutAction, stake)
+		}
+		switch c.InsuranceTimeoutAction {
+		case "":
+			c.InsuranceTimeoutAction = InsuranceTimeoutDecline
+		case InsuranceTimeoutDecline, InsuranceTimeoutInsure:
+		default:
+			return nil, fmt.Errorf("unknown insurance timeout action %q of stake %d", c.InsuranceTimeoutAction, stake)
+		}
+		configs[stake] = c
+	}
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("no table config")
+	}
+	return configs, nil
+}
+
+// SetTableConfigs replaces the table configs, nil restores the default
+func SetTableConfigs(configs map[int64]TableConfig) {
+	tableConfigs.Set(configs)
+}
+
+// TableConfigForStake returns the config of the highest stake level not above markUnit
+func TableConfigForStake(markUnit int64) TableConfig {
+	return tableConfigs.For(markUnit)
+}
+
+func (c TableConfig) InsuranceDuration() time.Duration {
+	return time.Duration(c.InsuranceSeconds) * time.Second
+}
+
+func (c TableConfig) TurnDuration() time.Duration {
+	return time.Duration(c.TurnSeconds) * time.Second
+}
+
+type timeBank struct {
+	seconds int
+	// rounds played since the last top up
+	rounds int
+}
+
+// TableConfig returns the config of the stake of the table
+func (s *MatchState) TableConfig() TableConfig {
+	return TableConfigForStake(int64(s.Label.MarkUnit))
+}
+
+// bank returns the time bank of userId, full the first time the player sits at the table.
+// Banks are kept when the player leaves so rejoining does not refill them.
+func (s *MatchState) bank(userId string) *timeBank {
+	bank, ok := s.timeBanks[userId]
+	if !ok {
+		bank = &timeBank{seconds: s.TableConfig().TimeBankSeconds}
+		s.timeBanks[userId] = bank
+	}
+	return bank
+}
+
+// GetTimeBank returns the seconds left in the time bank of userId
+func (s *MatchState) GetTimeBank(userId string) int {
+	return s.bank(userId).seconds
+}
+
+// CountTimeBankRound counts a round played by userId, topping the bank up every few rounds
+func (s *MatchState) CountTimeBankRound(userId string) {
+	cfg := s.TableConfig()
+	bank := s.bank(userId)
+	if cfg.TimeBankTopUpRounds <= 0 || cfg.TimeBankTopUpSeconds <= 0 {
+		return
+	}
+	bank.rounds++
+	if bank.rounds < cfg.TimeBankTopUpRounds {
+		return
+	}
+	bank.rounds = 0
+	bank.seconds += cfg.TimeBankTopUpSeconds
+	if bank.seconds > cfg.TimeBankSeconds {
+		bank.seconds = cfg.TimeBankSeconds
+	}
+}
+
+// DrawTimeBank takes an extension for the turn of userId out of its bank, 0 when the bank is empty
+func (s *MatchState) DrawTimeBank(userId string) int {
+	bank := s.bank(userId)
+	extension := s.TableConfig().TimeBankExtensionSeconds
+	if extension > bank.seconds {
+		extension = bank.seconds
+	}
+	bank.seconds -= extension
+	return extension
+}
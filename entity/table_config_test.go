@@ -0,0 +1,68 @@
+package entity
+
+import "testing"
+
+func TestParseTableConfigs(t *testing.T) {
+	configs, err := ParseTableConfigs(`{"0":{"insurance_seconds":5,"turn_seconds":10},"1000":{"insurance_seconds":8,"turn_seconds":20,"time_bank_seconds":60,"persistent_shoe":true}}`)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	SetTableConfigs(configs)
+	defer SetTableConfigs(nil)
+	if cfg := TableConfigForStake(500); cfg.TurnDuration().Seconds() != 10 {
+		t.Errorf("Expected the base config below 1000, got %+v", cfg)
+	}
+	if cfg := TableConfigForStake(500); cfg.PersistentShoe {
+		t.Errorf("Expected a new shoe every round by default")
+	}
+	if cfg := TableConfigForStake(5000); cfg.InsuranceSeconds != 8 || cfg.TimeBankSeconds != 60 || !cfg.PersistentShoe {
+		t.Errorf("Expected the high stake config, got %+v", cfg)
+	}
+
+	for _, raw := range []string{`{}`, `{"0":{"turn_seconds":0,"insurance_seconds":5}}`, `{"0":{"turn_seconds":10,"insurance_seconds":5,"time_bank_seconds":-1}}`} {
+		if _, err := ParseTableConfigs(raw); err == nil {
+			t.Errorf("Expected %s to be rejected", raw)
+		}
+	}
+}
+
+func TestTimeBank(t *testing.T) {
+	SetTableConfigs(map[int64]TableConfig{0: {
+		InsuranceSeconds:         5,
+		TurnSeconds:              10,
+		TimeBankSeconds:          20,
+		TimeBankTopUpSeconds:     5,
+		TimeBankTopUpRounds:      2,
+		TimeBankExtensionSeconds: 15,
+	}})
+	defer SetTableConfigs(nil)
+	state := newTestTable()
+
+	if n := state.GetTimeBank("p1"); n != 20 {
+		t.Fatalf("Expected a full bank, got %d", n)
+	}
+	if n := state.DrawTimeBank("p1"); n != 15 || state.GetTimeBank("p1") != 5 {
+		t.Errorf("Expected a 15s extension leaving 5s, got %d leaving %d", n, state.GetTimeBank("p1"))
+	}
+	if n := state.DrawTimeBank("p1"); n != 5 {
+		t.Errorf("Expected the last 5s, got %d", n)
+	}
+	if n := state.DrawTimeBank("p1"); n != 0 {
+		t.Errorf("Expected an empty bank, got %d", n)
+	}
+
+	state.CountTimeBankRound("p1")
+	if n := state.GetTimeBank("p1"); n != 0 {
+		t.Errorf("Expected no top up before 2 rounds, got %d", n)
+	}
+	state.CountTimeBankRound("p1")
+	if n := state.GetTimeBank("p1"); n != 5 {
+		t.Errorf("Expected a 5s top up, got %d", n)
+	}
+	for i := 0; i < 10; i++ {
+		state.CountTimeBankRound("p1")
+	}
+	if n := state.GetTimeBank("p1"); n != 20 {
+		t.Errorf("Expected the bank capped at 20s, got %d", n)
+	}
+}
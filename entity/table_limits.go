@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"fmt"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+// TableLimits bounds the main bet of a player in chips, 0 leaves a side unbounded
+type TableLimits struct {
+	MinBet int64 `json:"min_bet"`
+	MaxBet int64 `json:"max_bet"`
+}
+
+// TableLimitsUpdate is broadcast when the limits change and sent to a player whose bet
+// they refused, with the main bet the player asked for
+type TableLimitsUpdate struct {
+	Limits   *TableLimits `json:"limits"`
+	Rejected int64        `json:"rejected,omitempty"`
+}
+
+func (l TableLimits) Validate() error {
+	if l.MinBet < 0 || l.MaxBet < 0 {
+		return fmt.Errorf("negative table limit")
+	}
+	if l.MaxBet > 0 && l.MinBet > l.MaxBet {
+		return fmt.Errorf("min bet %d above max bet %d", l.MinBet, l.MaxBet)
+	}
+	return nil
+}
+
+// SetTableLimits changes the limits of the table, both 0 removes them
+func (s *MatchState) SetTableLimits(l TableLimits) {
+	if l.MinBet == 0 && l.MaxBet == 0 {
+		s.limits = nil
+		return
+	}
+	s.limits = &l
+}
+
+// GetTableLimits returns the limits of the table, nil without any
+func (s *MatchState) GetTableLimits() *TableLimits {
+	return s.limits
+}
+
+// MainBetAfter returns the main bet of userId once the bet request is placed
+func (s *MatchState) MainBetAfter(userId string, code pb.BlackjackBetCode, chips int64) int64 {
+	current := int64(0)
+	if bet, found := s.userBets[userId]; found {
+		current = bet.First
+	}
+	switch code {
+	case pb.BlackjackBetCode_BLACKJACK_BET_DOUBLE:
+		if current > 0 {
+			return current * 2
+		}
+		return s.userLastBets[userId] * 2
+	case pb.BlackjackBetCode_BLACKJACK_BET_REBET:
+		return s.userLastBets[userId]
+	}
+	return current + chips
+}
+
+// IsWithinTableLimits reports whether a main bet of total chips is allowed at the table
+func (s *MatchState) IsWithinTableLimits(total int64) bool {
+	if s.limits == nil {
+		return true
+	}
+	if s.limits.MinBet > 0 && total < s.limits.MinBet {
+		return false
+	}
+	return s.limits.MaxBet == 0 || total <= s.limits.MaxBet
+}
@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"testing"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func TestTableLimitsValidate(t *testing.T) {
+	for _, l := range []TableLimits{{MinBet: -1}, {MaxBet: -1}, {MinBet: 50, MaxBet: 10}} {
+		if l.Validate() == nil {
+			t.Errorf("%+v should be refused", l)
+		}
+	}
+	for _, l := range []TableLimits{{}, {MinBet: 10}, {MaxBet: 10}, {MinBet: 10, MaxBet: 10}} {
+		if err := l.Validate(); err != nil {
+			t.Errorf("%+v refused: %v", l, err)
+		}
+	}
+}
+
+func TestTableLimitsBets(t *testing.T) {
+	state := newTestTable()
+	if !state.IsWithinTableLimits(1_000_000) || state.GetTableLimits() != nil {
+		t.Fatalf("a table without limits takes any bet")
+	}
+	state.SetTableLimits(TableLimits{MinBet: 20, MaxBet: 100})
+
+	if state.IsWithinTableLimits(state.MainBetAfter("a", pb.BlackjackBetCode_BLACKJACK_BET_NORMAL, 10)) {
+		t.Errorf("an opening bet under the min should be refused")
+	}
+	state.userBets["a"] = &pb.BlackjackPlayerBet{UserId: "a", First: 60}
+	if got := state.MainBetAfter("a", pb.BlackjackBetCode_BLACKJACK_BET_NORMAL, 10); got != 70 || !state.IsWithinTableLimits(got) {
+		t.Errorf("adding 10 to 60 = %d, should be allowed", got)
+	}
+	if got := state.MainBetAfter("a", pb.BlackjackBetCode_BLACKJACK_BET_DOUBLE, 0); got != 120 || state.IsWithinTableLimits(got) {
+		t.Errorf("doubling 60 = %d, should be over the max", got)
+	}
+	state.userLastBets["b"] = 40
+	if got := state.MainBetAfter("b", pb.BlackjackBetCode_BLACKJACK_BET_REBET, 0); got != 40 || !state.IsWithinTableLimits(got) {
+		t.Errorf("rebet of 40 = %d, should be allowed", got)
+	}
+
+	state.SetTableLimits(TableLimits{})
+	if state.GetTableLimits() != nil {
+		t.Errorf("zero limits should remove them")
+	}
+}
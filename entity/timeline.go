@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"sort"
+	"time"
+)
+
+// Timeline steps announced to clients
+const (
+	TimelineStepDeal        = "deal"
+	TimelineStepDealerDeal  = "dealer_deal"
+	TimelineStepReveal      = "reveal"
+	TimelineStepDealerDraw  = "dealer_draw"
+	TimelineStepDealerTotal = "dealer_total"
+	TimelineStepSettle      = "settle"
+	TimelineStepTurns       = "turns"
+)
+
+// Pacing of the timeline steps, clients animate each step before the next one shows
+var (
+	DealPace       = 400 * time.Millisecond
+	DealSettle     = 1500 * time.Millisecond
+	RevealPace     = 800 * time.Millisecond
+	DealerDrawPace = 700 * time.Millisecond
+)
+
+// TimelineStep is a step of the timeline, ShowAt is its offset from StartedAt
+type TimelineStep struct {
+	Step   string `json:"step"`
+	UserId string `json:"user_id,omitempty"`
+	ShowAt int64  `json:"show_at_ms"`
+}
+
+// TimelineUpdate announces the steps of a timeline when it starts
+type TimelineUpdate struct {
+	StartedAt int64           `json:"started_at"`
+	Steps     []*TimelineStep `json:"steps"`
+}
+
+type timelineEvent struct {
+	at  time.Time
+	seq int
+	fn  func()
+}
+
+// Timeline runs the scheduled steps of the match on the ticks following their time,
+// so the match keeps handling messages while clients animate
+type Timeline struct {
+	start  time.Time
+	seq    int
+	steps  []*TimelineStep
+	events []*timelineEvent
+}
+
+func NewTimeline() *Timeline {
+	return &Timeline{}
+}
+
+// Start begins a new timeline at now, steps left from the previous one are dropped
+func (t *Timeline) Start(now time.Time) {
+	t.start = now
+	t.seq = 0
+	t.steps = nil
+	t.events = nil
+}
+
+// Schedule runs fn on the first tick from offset after the start
+func (t *Timeline) Schedule(step, userId string, offset time.Duration, fn func()) {
+	t.seq++
+	t.events = append(t.events, &timelineEvent{at: t.start.Add(offset), seq: t.seq, fn: fn})
+	t.steps = append(t.steps, &TimelineStep{Step: step, UserId: userId, ShowAt: offset.Milliseconds()})
+}
+
+// Run fires the due steps in time order and returns how many fired
+func (t *Timeline) Run(now time.Time) int {
+	sort.SliceStable(t.events, func(i, j int) bool {
+		if t.events[i].at.Equal(t.events[j].at) {
+			return t.events[i].seq < t.events[j].seq
+		}
+		return t.events[i].at.Before(t.events[j].at)
+	})
+	fired := 0
+	for len(t.events) > 0 && !t.events[0].at.After(now) {
+		event := t.events[0]
+		t.events = t.events[1:]
+		event.fn()
+		fired++
+	}
+	return fired
+}
+
+// Done reports whether every scheduled step fired
+func (t *Timeline) Done() bool {
+	return len(t.events) == 0
+}
+
+// Update lists the steps of the timeline for clients
+func (t *Timeline) Update() *TimelineUpdate {
+	return &TimelineUpdate{StartedAt: t.start.UnixMilli(), Steps: t.steps}
+}
+
+func (s *MatchState) Timeline() *Timeline {
+	return s.timeline
+}
@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimelineRunsDueStepsInOrder(t *testing.T) {
+	now := time.Now()
+	tl := NewTimeline()
+	tl.Start(now)
+	fired := make([]string, 0)
+	tl.Schedule(TimelineStepDealerDeal, "", 2*DealPace, func() { fired = append(fired, "dealer") })
+	tl.Schedule(TimelineStepDeal, "p1", 0, func() { fired = append(fired, "p1") })
+	tl.Schedule(TimelineStepDeal, "p2", DealPace, func() { fired = append(fired, "p2") })
+
+	if n := tl.Run(now); n != 1 || fired[0] != "p1" {
+		t.Fatalf("expected only the first deal at start, fired %v", fired)
+	}
+	if tl.Done() {
+		t.Fatal("timeline should wait for the dealer deal")
+	}
+	if n := tl.Run(now.Add(3 * DealPace)); n != 2 {
+		t.Fatalf("expected the remaining 2 steps, got %d", n)
+	}
+	if fired[1] != "p2" || fired[2] != "dealer" {
+		t.Fatalf("steps fired out of order: %v", fired)
+	}
+	if !tl.Done() {
+		t.Fatal("timeline should be done")
+	}
+
+	update := tl.Update()
+	if update.StartedAt != now.UnixMilli() || len(update.Steps) != 3 {
+		t.Fatalf("unexpected update %+v", update)
+	}
+	if update.Steps[0].ShowAt != (2*DealPace).Milliseconds() || update.Steps[1].UserId != "p1" {
+		t.Fatalf("unexpected steps %+v %+v", update.Steps[0], update.Steps[1])
+	}
+
+	tl.Start(now)
+	if !tl.Done() || len(tl.Update().Steps) != 0 {
+		t.Fatal("start should drop the previous steps")
+	}
+}
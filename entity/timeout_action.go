@@ -0,0 +1,75 @@
+package entity
+
+import (
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+// ActionUpdate tells the table about an action played for a player,
+// Timeout is set when the player let the turn run out
+type ActionUpdate struct {
+	UserId string                 `json:"user_id"`
+	HandN0 pb.BlackjackHandN0     `json:"hand_n0"`
+	Code   pb.BlackjackActionCode `json:"code"`
+	// Forfeit is set with a stay when the hand is given up, Decline with an insurance not taken
+	Forfeit bool `json:"forfeit,omitempty"`
+	Decline bool `json:"decline,omitempty"`
+	Timeout bool `json:"timeout"`
+}
+
+// TimeoutAction returns the action played for userId when its turn runs out, following the table config
+func (s *MatchState) TimeoutAction(userId string) *ActionUpdate {
+	update := &ActionUpdate{
+		UserId:  userId,
+		HandN0:  s.currentHand[userId],
+		Code:    pb.BlackjackActionCode_BLACKJACK_ACTION_STAY,
+		Timeout: true,
+	}
+	switch policy := s.TableConfig().TimeoutAction; policy {
+	case TimeoutForfeit:
+		update.Forfeit = true
+	default:
+		update.Code = s.playFor(userId, s.GetLegalActionsByUserId(userId), policy)
+	}
+	return update
+}
+
+// InsuranceTimeoutAction returns the insurance action played for userId when the insurance round runs out
+func (s *MatchState) InsuranceTimeoutAction(userId string) *ActionUpdate {
+	return &ActionUpdate{
+		UserId:  userId,
+		HandN0:  pb.BlackjackHandN0_BLACKJACK_HAND_UNSPECIFIED,
+		Code:    pb.BlackjackActionCode_BLACKJACK_ACTION_INSURANCE,
+		Decline: s.TableConfig().InsuranceTimeoutAction != InsuranceTimeoutInsure,
+		Timeout: true,
+	}
+}
+
+// IsInsurancePending reports whether userId can still take the insurance offered,
+// balance is what is left in its wallet
+func (s *MatchState) IsInsurancePending(userId string, balance int64) bool {
+	return s.IsAllowInsurance() && s.IsBet(userId) && !s.HasInsuranceBet(userId) && s.IsCanInsuranceBet(userId, balance)
+}
+
+// MarkTimedOut records that the turn of userId ran out on its current hand,
+// the rest of that hand is played for it
+func (s *MatchState) MarkTimedOut(userId string) {
+	s.timedOut[userId] = s.currentHand[userId]
+}
+
+// IsPlayedOnTimeout reports whether the current hand of userId is played for it after a timeout
+func (s *MatchState) IsPlayedOnTimeout(userId string) bool {
+	handN0, ok := s.timedOut[userId]
+	return ok && handN0 == s.currentHand[userId]
+}
+
+// ForfeitHand gives up a hand of userId, it loses its bet whatever the dealer has
+func (s *MatchState) ForfeitHand(userId string, handN0 pb.BlackjackHandN0) {
+	if s.forfeits[userId] == nil {
+		s.forfeits[userId] = make(map[pb.BlackjackHandN0]bool)
+	}
+	s.forfeits[userId][handN0] = true
+}
+
+func (s *MatchState) IsForfeited(userId string, handN0 pb.BlackjackHandN0) bool {
+	return s.forfeits[userId][handN0]
+}
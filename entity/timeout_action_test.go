@@ -0,0 +1,113 @@
+package entity
+
+import (
+	"testing"
+
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+func newTimeoutState(t *testing.T, action string, player []pb.CardRank) *MatchState {
+	t.Helper()
+	cfg := DefaultTableConfig
+	cfg.TimeoutAction = action
+	SetTableConfigs(map[int64]TableConfig{0: cfg})
+	state := newTestTable("p1")
+	state.userBets["p1"] = &pb.BlackjackPlayerBet{UserId: "p1", First: 100}
+	state.SetupMatchPresence()
+	state.Init()
+	state.AddCards([]*pb.Card{
+		{Rank: pb.CardRank_RANK_K, Suit: pb.CardSuit_SUIT_SPADES},
+		{Rank: pb.CardRank_RANK_7, Suit: pb.CardSuit_SUIT_HEARTS},
+	}, "", pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
+	cards := make([]*pb.Card, 0, len(player))
+	for _, rank := range player {
+		cards = append(cards, &pb.Card{Rank: rank, Suit: pb.CardSuit_SUIT_CLUBS})
+	}
+	state.AddCards(cards, "p1", pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
+	state.SetCurrentTurn("p1")
+	return state
+}
+
+func TestTimeoutAction(t *testing.T) {
+	defer SetTableConfigs(nil)
+	twelve := []pb.CardRank{pb.CardRank_RANK_10, pb.CardRank_RANK_2}
+
+	update := newTimeoutState(t, TimeoutStand, twelve).TimeoutAction("p1")
+	if update.Code != pb.BlackjackActionCode_BLACKJACK_ACTION_STAY || update.Forfeit || !update.Timeout {
+		t.Errorf("Expected a timed out stay, got %+v", update)
+	}
+	update = newTimeoutState(t, TimeoutBasicStrategy, twelve).TimeoutAction("p1")
+	if update.Code != pb.BlackjackActionCode_BLACKJACK_ACTION_HIT {
+		t.Errorf("Expected basic strategy to hit 12 against a 10, got %+v", update)
+	}
+	update = newTimeoutState(t, TimeoutForfeit, twelve).TimeoutAction("p1")
+	if update.Code != pb.BlackjackActionCode_BLACKJACK_ACTION_STAY || !update.Forfeit {
+		t.Errorf("Expected a forfeit, got %+v", update)
+	}
+
+	insurance := newTimeoutState(t, TimeoutStand, twelve).InsuranceTimeoutAction("p1")
+	if insurance.Code != pb.BlackjackActionCode_BLACKJACK_ACTION_INSURANCE || !insurance.Decline {
+		t.Errorf("Expected insurance to be declined by default, got %+v", insurance)
+	}
+}
+
+func TestForfeitedHandLoses(t *testing.T) {
+	defer SetTableConfigs(nil)
+	state := newTimeoutState(t, TimeoutForfeit, []pb.CardRank{pb.CardRank_RANK_10, pb.CardRank_RANK_K})
+	if r := state.CalcGameFinish().BetResults[0].First; r.IsWin != 1 {
+		t.Fatalf("Expected 20 to beat 17, got %+v", r)
+	}
+	state.ForfeitHand("p1", pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
+	if r := state.CalcGameFinish().BetResults[0].First; r.IsWin != -1 || r.WinAmount != -100 {
+		t.Errorf("Expected the forfeited hand to lose its bet, got %+v", r)
+	}
+	state.Init()
+	if state.IsForfeited("p1", pb.BlackjackHandN0_BLACKJACK_HAND_1ST) {
+		t.Error("Expected forfeits to be cleared for the next round")
+	}
+}
+
+func TestParseTableConfigTimeoutActions(t *testing.T) {
+	configs, err := ParseTableConfigs(`{"0":{"insurance_seconds":5,"turn_seconds":10}}`)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if c := configs[0]; c.TimeoutAction != TimeoutStand || c.InsuranceTimeoutAction != InsuranceTimeoutDecline {
+		t.Errorf("Expected stand and decline by default, got %+v", c)
+	}
+	if _, err := ParseTableConfigs(`{"0":{"insurance_seconds":5,"turn_seconds":10,"timeout_action":"fold"}}`); err == nil {
+		t.Error("Expected an unknown timeout action to be rejected")
+	}
+}
+
+func TestPlayedOnTimeout(t *testing.T) {
+	defer SetTableConfigs(nil)
+	state := newTimeoutState(t, TimeoutBasicStrategy, []pb.CardRank{pb.CardRank_RANK_8, pb.CardRank_RANK_8})
+	if state.IsPlayedOnTimeout("p1") {
+		t.Fatalf("a hand is only played for the player once its turn ran out")
+	}
+	state.MarkTimedOut("p1")
+	if !state.IsPlayedOnTimeout("p1") {
+		t.Errorf("the timed out hand should be played for the player")
+	}
+	state.SetCurrentHandN0("p1", pb.BlackjackHandN0_BLACKJACK_HAND_2ND)
+	if state.IsPlayedOnTimeout("p1") {
+		t.Errorf("the player gets its turn back on the next hand")
+	}
+}
+
+func TestInsurancePending(t *testing.T) {
+	defer SetTableConfigs(nil)
+	state := newTimeoutState(t, TimeoutStand, []pb.CardRank{pb.CardRank_RANK_10, pb.CardRank_RANK_2})
+	if state.IsInsurancePending("p1", 1000) {
+		t.Errorf("no insurance is pending before it is offered")
+	}
+	state.SetAllowInsurance(true)
+	if !state.IsInsurancePending("p1", 1000) || state.IsInsurancePending("p1", 10) {
+		t.Errorf("insurance is pending for a player who can afford it")
+	}
+	state.InsuranceBet("p1")
+	if state.IsInsurancePending("p1", 1000) {
+		t.Errorf("insurance taken is no longer pending")
+	}
+}
@@ -3,10 +3,13 @@ package main
 import (
 	"context"
 	"database/sql"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/heroiclabs/nakama-common/runtime"
 	"github.com/nk-nigeria/blackjack-module/api"
+	"github.com/nk-nigeria/blackjack-module/cgbdb"
 	"github.com/nk-nigeria/blackjack-module/entity"
 	"github.com/nk-nigeria/blackjack-module/pkg/global"
 	"github.com/nk-nigeria/blackjack-module/usecase/service"
@@ -28,14 +31,143 @@ func InitModule(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runti
 		return err
 	}
 
+	if err := cgbdb.InitBotBankrollTables(ctx, logger, db); err != nil {
+		return err
+	}
+	if err := cgbdb.InitBotWalletLedgerTable(ctx, logger, db); err != nil {
+		return err
+	}
+	if err := cgbdb.InitBotPnlDailyTable(ctx, logger, db); err != nil {
+		return err
+	}
+	if err := cgbdb.InitChatReportTable(ctx, logger, db); err != nil {
+		return err
+	}
+	if err := cgbdb.InitMatchSignalAuditTable(ctx, logger, db); err != nil {
+		return err
+	}
+
 	// Initialize BotLoader for blackjack
-	entity.BotLoader = bot.NewBotLoader(db, define.BlackjackName.String(), 100000)
+	entity.BotLoader = bot.NewBotLoader(db, define.BlackjackName.String(), entity.BotLoaderMinChip)
+
+	// Runtime config of the module, every setting falls back to its default when unset or invalid
+	env, _ := ctx.Value(runtime.RUNTIME_CTX_ENV).(map[string]string)
+
+	// Deal one shoe over several rounds until its cut card, e.g. true
+	if raw, ok := env["BLACKJACK_PERSISTENT_SHOE"]; ok && raw != "" {
+		if on, err := strconv.ParseBool(raw); err != nil {
+			logger.WithField("err", err).Error("invalid persistent shoe, using defaults")
+		} else {
+			entity.SetPersistentShoe(on)
+		}
+	}
+
+	// Personality mix of seated bots, e.g. {"cautious":30,"novice":70}
+	if raw, ok := env["BLACKJACK_BOT_PERSONALITY_WEIGHTS"]; ok && raw != "" {
+		if weights, err := entity.ParseBotPersonalityWeights(raw); err != nil {
+			logger.WithField("err", err).Error("invalid bot personality weights, using defaults")
+		} else {
+			entity.SetBotPersonalityWeights(weights)
+		}
+	}
+
+	// Per personality mistake rates, e.g. {"novice":0.3,"cautious":0.02}
+	if raw, ok := env["BLACKJACK_BOT_MISTAKE_RATES"]; ok && raw != "" {
+		if rates, err := entity.ParseBotMistakeRates(raw); err != nil {
+			logger.WithField("err", err).Error("invalid bot mistake rates, using defaults")
+		} else {
+			entity.SetBotMistakeRates(rates)
+		}
+	}
+
+	// Bot fill policy per stake level, e.g. {"0":{"min_occupied":2,"free_seats_for_humans":1}}
+	if raw, ok := env["BLACKJACK_BOT_FILL_POLICIES"]; ok && raw != "" {
+		if policies, err := entity.ParseBotFillPolicies(raw); err != nil {
+			logger.WithField("err", err).Error("invalid bot fill policies, using defaults")
+		} else {
+			entity.SetBotFillPolicies(policies)
+		}
+	}
+
+	// AFK policy per stake level, e.g. {"0":{"warn_rounds":1,"sit_out_rounds":2,"kick_rounds":4}}
+	if raw, ok := env["BLACKJACK_AFK_POLICIES"]; ok && raw != "" {
+		if policies, err := entity.ParseAfkPolicies(raw); err != nil {
+			logger.WithField("err", err).Error("invalid afk policies, using defaults")
+		} else {
+			entity.SetAfkPolicies(policies)
+		}
+	}
+
+	// Turn timers, time bank, timeout actions and shoe per stake level, e.g. {"0":{"turn_seconds":10,"insurance_seconds":5}}
+	if raw, ok := env["BLACKJACK_TABLE_CONFIGS"]; ok && raw != "" {
+		if configs, err := entity.ParseTableConfigs(raw); err != nil {
+			logger.WithField("err", err).Error("invalid table configs, using defaults")
+		} else {
+			entity.SetTableConfigs(configs)
+		}
+	}
+
+	// Seats of tables whose label does not ask for 5 or 7
+	if raw, ok := env["BLACKJACK_TABLE_SEATS"]; ok && raw != "" {
+		if n, err := strconv.Atoi(raw); err != nil {
+			logger.WithField("err", err).Error("invalid BLACKJACK_TABLE_SEATS")
+		} else if seatsErr := entity.SetTableSeats(n); seatsErr != nil {
+			logger.WithField("err", seatsErr).Error("invalid BLACKJACK_TABLE_SEATS")
+		}
+	}
+
+	// Rounds and minutes a seat stays reserved for a player sitting out, 0 disables a limit
+	sitOutRounds, sitOutDuration := -1, time.Duration(-1)
+	if raw, ok := env["BLACKJACK_SIT_OUT_ROUNDS"]; ok && raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n >= 0 {
+			sitOutRounds = n
+		} else {
+			logger.WithField("value", raw).Error("invalid BLACKJACK_SIT_OUT_ROUNDS")
+		}
+	}
+	if raw, ok := env["BLACKJACK_SIT_OUT_MINUTES"]; ok && raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n >= 0 {
+			sitOutDuration = time.Duration(n) * time.Minute
+		} else {
+			logger.WithField("value", raw).Error("invalid BLACKJACK_SIT_OUT_MINUTES")
+		}
+	}
+	entity.SetSitOutLimits(sitOutRounds, sitOutDuration)
+
+	// Seat hold of players dropping mid-round and how their hands are played meanwhile
+	grace, autoPlay := entity.DefaultDisconnectGrace, entity.DefaultAutoPlay
+	if raw, ok := env["BLACKJACK_DISCONNECT_GRACE_SECONDS"]; ok && raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil {
+			grace = time.Duration(n) * time.Second
+		} else {
+			logger.WithField("value", raw).Error("invalid BLACKJACK_DISCONNECT_GRACE_SECONDS")
+		}
+	}
+	if raw, ok := env["BLACKJACK_AUTO_PLAY"]; ok && raw != "" {
+		autoPlay = raw
+	}
+	if err := entity.SetDisconnectPolicy(grace, autoPlay); err != nil {
+		logger.WithField("err", err).Error("invalid disconnect policy, using defaults")
+	}
+
+	// Table chat word filter, comma separated
+	if raw, ok := env["BLACKJACK_CHAT_BANNED_WORDS"]; ok && raw != "" {
+		entity.SetChatBannedWords(strings.Split(raw, ","))
+	}
+
+	// Secret operators sign match signals with, signals are refused without one
+	if raw, ok := env["BLACKJACK_SIGNAL_SECRET"]; ok && raw != "" {
+		entity.SetMatchSignalSecret(raw)
+	}
+
+	// Users allowed to call the admin RPCs, comma separated, super admins may see the hole card
+	entity.SetAdmins(entity.ParseAdminIds(env["BLACKJACK_ADMIN_IDS"]), entity.ParseAdminIds(env["BLACKJACK_SUPER_ADMIN_IDS"]))
+	if err := api.RegisterAdminRpcs(initializer); err != nil {
+		return err
+	}
 
-	// Initialize bot integration service and set it globally
-	botIntegration := service.NewBlackjackBotIntegration(db)
-	// Set the global bot integration in state machine package
-	global.SetGlobalBotIntegration(botIntegration)
-	// This will be done when the first match starts
+	// Bot integrations are created per match, sharing one bot config
+	global.SetBotIntegrationRegistry(service.NewBotIntegrationRegistry(db, logger))
 
 	logger.Info("Plugin loaded in '%d' msec.", time.Since(initStart).Milliseconds())
 	return nil
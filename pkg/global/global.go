@@ -6,20 +6,20 @@ import (
 
 // Global variables for bot integration
 var (
-	globalBotIntegration interface{}
-	globalMutex          sync.RWMutex
+	botIntegrationRegistry interface{}
+	globalMutex            sync.RWMutex
 )
 
-// GetGlobalBotIntegration returns the global bot integration instance
-func GetGlobalBotIntegration() interface{} {
+// GetBotIntegrationRegistry returns the registry of per-match bot integrations
+func GetBotIntegrationRegistry() interface{} {
 	globalMutex.RLock()
 	defer globalMutex.RUnlock()
-	return globalBotIntegration
+	return botIntegrationRegistry
 }
 
-// SetGlobalBotIntegration sets the global bot integration instance
-func SetGlobalBotIntegration(botIntegration interface{}) {
+// SetBotIntegrationRegistry sets the registry of per-match bot integrations
+func SetBotIntegrationRegistry(registry interface{}) {
 	globalMutex.Lock()
 	defer globalMutex.Unlock()
-	globalBotIntegration = botIntegration
+	botIntegrationRegistry = registry
 }
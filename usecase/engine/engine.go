@@ -15,12 +15,23 @@ func NewGameEngine() UseCase {
 }
 
 func (m *Engine) NewGame(s *entity.MatchState) error {
-	m.deck = entity.NewDeck()
-	m.deck.Shuffle()
+	// a persistent shoe carries over between rounds until the cut card is reached
+	if m.deck == nil || !s.KeepShoe() || m.deck.NeedReshuffle() {
+		m.deck = entity.NewDeck()
+		m.deck.Shuffle()
+		s.OnShoeShuffled(m.deck.Remaining())
+	}
 	s.Init()
 	return nil
 }
 
+func (m *Engine) CardsRemaining() int {
+	if m.deck == nil {
+		return 0
+	}
+	return m.deck.Remaining()
+}
+
 func (m *Engine) Deal(amount int) []*pb.Card {
 	list, err := m.deck.Deal(amount)
 	if err != nil {
@@ -51,15 +62,15 @@ func (m *Engine) RejoinUserMessage(s *entity.MatchState, userId string) map[pb.O
 			IsBanker:      false,
 		}
 
-		if s.GetCurrentTurn() == userId {
+		if s.IsInTurn(userId) {
 			messages[pb.OpCodeUpdate_OPCODE_UPDATE_TABLE] = &pb.BlackjackUpdateDesk{
 				IsInsuranceTurnEnter: s.IsAllowInsurance(),
-				InTurn:               s.GetCurrentTurn(),
-				Hand_N0:              s.GetCurrentHandN0(s.GetCurrentTurn()),
+				InTurn:               userId,
+				Hand_N0:              s.GetCurrentHandN0(userId),
 				IsUpdateLegalAction:  true,
 				Actions: &pb.BlackjackLegalActions{
-					UserId:  s.GetCurrentTurn(),
-					Actions: s.GetLegalActions(),
+					UserId:  userId,
+					Actions: s.GetLegalActionsByUserId(userId),
 				},
 				PlayersBet: s.GetPlayersBet(),
 			}
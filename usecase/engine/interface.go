@@ -9,6 +9,7 @@ import (
 type UseCase interface {
 	NewGame(s *entity.MatchState) error
 	Deal(amount int) []*pb.Card
+	CardsRemaining() int
 	Finish(s *entity.MatchState) *pb.BlackjackUpdateFinish
 	Draw(s *entity.MatchState, userId string, handN0 pb.BlackjackHandN0)
 	DoubleDown(s *entity.MatchState, userId string, handN0 pb.BlackjackHandN0) int64
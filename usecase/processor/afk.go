@@ -0,0 +1,89 @@
+package processor
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/nk-nigeria/blackjack-module/entity"
+)
+
+// ProcessAfk counts the round dealt since the last one and applies the AFK policy of the table,
+// idle players are warned, sat out or marked to leave with a kick reason
+func (m *BaseProcessor) ProcessAfk(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	db *sql.DB,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+) {
+	s.CountAfkRound()
+	sitOut := false
+	for _, d := range s.CheckAfk(time.Now()) {
+		presence := s.GetPresence(d.UserId)
+		if presence == nil {
+			continue
+		}
+		log := logger.WithField("user_id", d.UserId).WithField("idle_rounds", d.IdleRounds)
+		switch d.Action {
+		case entity.AfkActionWarn:
+			log.Info("warn idle user")
+			m.broadcastJson(logger, dispatcher, entity.OpCodeUpdateAfkWarning,
+				d.Warning, []runtime.Presence{presence}, true)
+		case entity.AfkActionSitOut:
+			if s.SitOut(d.UserId) == "" {
+				log.Info("sit out idle user")
+				sitOut = true
+			}
+		case entity.AfkActionKick:
+			log.WithField("reason", d.Reason).Info("kick idle user")
+			s.SetKickReason(d.UserId, d.Reason)
+			s.AddLeavePresence(presence)
+		}
+	}
+	if sitOut {
+		m.notifySitOut(logger, dispatcher, s, nil)
+	}
+}
+
+// notifyKickReason tells the kicked presences why they leave the table
+func (m *BaseProcessor) notifyKickReason(logger runtime.Logger,
+	dispatcher runtime.MatchDispatcher,
+	reason string,
+	presences ...runtime.Presence,
+) {
+	if len(presences) == 0 {
+		return
+	}
+	m.broadcastJson(logger, dispatcher, entity.OpCodeUpdateKickReason,
+		&entity.KickReason{Reason: reason}, presences, true)
+}
+
+// presencesBelowChips returns the presences whose wallet holds less than minChip
+func (m *BaseProcessor) presencesBelowChips(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	minChip int64,
+	presences []runtime.Presence,
+) []runtime.Presence {
+	userIds := make([]string, 0, len(presences))
+	for _, presence := range presences {
+		userIds = append(userIds, presence.GetUserId())
+	}
+	wallets, err := entity.ReadWalletUsers(ctx, nk, logger, userIds...)
+	if err != nil {
+		return nil
+	}
+	chips := make(map[string]int64, len(wallets))
+	for _, w := range wallets {
+		chips[w.UserId] = w.Chips
+	}
+	below := make([]runtime.Presence, 0)
+	for _, presence := range presences {
+		if v, found := chips[presence.GetUserId()]; found && v < minChip {
+			below = append(below, presence)
+		}
+	}
+	return below
+}
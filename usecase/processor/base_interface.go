@@ -64,4 +64,12 @@ type IBaseProcessor interface {
 		dispatcher runtime.MatchDispatcher,
 		s *entity.MatchState,
 	)
+
+	ProcessAfk(ctx context.Context,
+		logger runtime.Logger,
+		nk runtime.NakamaModule,
+		db *sql.DB,
+		dispatcher runtime.MatchDispatcher,
+		s *entity.MatchState,
+	)
 }
@@ -51,6 +51,9 @@ func (m *BaseProcessor) ProcessApplyPresencesLeave(ctx context.Context,
 	db *sql.DB,
 	dispatcher runtime.MatchDispatcher,
 	s *entity.MatchState) {
+	// freed seats go to the seat queue once the leaves are applied
+	defer m.promoteSeatQueue(ctx, logger, nk, db, dispatcher, s)
+	s.HoldDisconnectedSeats(time.Now())
 	pendingLeaves := s.GetLeavePresences()
 	if len(pendingLeaves) == 0 {
 		return
@@ -63,11 +66,15 @@ func (m *BaseProcessor) ProcessApplyPresencesLeave(ctx context.Context,
 	listUserId := make([]string, 0)
 	for _, p := range pendingLeaves {
 		listUserId = append(listUserId, p.GetUserId())
+		s.Chat().Forget(p.GetUserId())
 	}
 	// cgbdb.UpdateUsersPlayingInMatch(ctx, logger, db, listUserId, "")
 	m.emitNkEvent(ctx, define.NakEventMatchLeave, nk, s, listUserId)
 
 	logger.Info("notify to player kick off %s", strings.Join(listUserId, ","))
+	for _, p := range pendingLeaves {
+		m.notifyKickReason(logger, dispatcher, s.PopKickReason(p.GetUserId()), p)
+	}
 	m.broadcastMessage(
 		logger, dispatcher,
 		int64(pb.OpCodeUpdate_OPCODE_KICK_OFF_THE_TABLE),
@@ -84,6 +91,12 @@ func (m *BaseProcessor) ProcessPresencesJoin(ctx context.Context,
 ) {
 	defer s.UpdateLabel()
 	logger.Info("process presences join %v", presences)
+	// joining and rejoining presences get the whole table once seated or watching
+	defer m.sendTableSnapshot(ctx, logger, nk, dispatcher, s, presences...)
+	presences = m.splitSpectators(logger, dispatcher, s, presences)
+	if len(presences) == 0 {
+		return
+	}
 	// update new presence
 	newJoins := make([]runtime.Presence, 0)
 	listUserId := make([]string, 0, len(presences))
@@ -101,19 +114,17 @@ func (m *BaseProcessor) ProcessPresencesJoin(ctx context.Context,
 	}
 	s.AddPresence(ctx, nk, db, newJoins)
 	s.JoinsInProgress -= len(newJoins)
+	for _, presence := range presences {
+		if s.Reconnect(presence.GetUserId()) {
+			logger.WithField("user_id", presence.GetUserId()).Info("user back on a held seat")
+			m.broadcastJson(logger, dispatcher, entity.OpCodeUpdateDisconnected,
+				&entity.DisconnectedUpdate{UserId: presence.GetUserId()}, nil, true)
+		}
+	}
 	// update match profile user
 	if len(listUserId) > 0 {
 		m.emitNkEvent(ctx, define.NakEventMatchJoin, nk, s, listUserId)
 	}
-	// for _, presence := range presences {
-	// 	messages := m.engine.RejoinUserMessage(s, presence.GetUserId())
-	// 	if messages == nil {
-	// 		continue
-	// 	}
-	// 	for k, msg := range messages {
-	// 		m.broadcastMessage(logger, dispatcher, int64(k), msg, []runtime.Presence{presence}, nil, true)
-	// 	}
-	// }
 	m.notifyUserChange(ctx, nk, logger, db, dispatcher, s, nil)
 }
 
@@ -126,10 +137,16 @@ func (m *BaseProcessor) ProcessPresencesLeave(ctx context.Context,
 	presences []runtime.Presence,
 ) {
 	defer s.UpdateLabel()
+	presences = m.removeSpectators(logger, dispatcher, s, presences)
+	if len(presences) == 0 {
+		return
+	}
+	defer m.promoteSeatQueue(ctx, logger, nk, db, dispatcher, s)
 	s.RemovePresences(presences...)
 	var listUserId []string
 	for _, p := range presences {
 		listUserId = append(listUserId, p.GetUserId())
+		s.PopKickReason(p.GetUserId())
 	}
 	m.emitNkEvent(ctx, define.NakEventMatchLeave, nk, s, listUserId)
 	// cgbdb.UpdateUsersPlayingInMatch(ctx, logger, db, listUserId, "")
@@ -146,12 +163,23 @@ func (m *BaseProcessor) ProcessPresencesLeavePending(ctx context.Context,
 ) {
 	defer s.UpdateLabel()
 	logger.Info("process presences leave pending %v", presences)
+	presences = m.removeSpectators(logger, dispatcher, s, presences)
+	if len(presences) == 0 {
+		return
+	}
 	userIdsLeave := make([]string, 0)
 	for _, presence := range presences {
 		_, found := s.PlayingPresences.Get(presence.GetUserId())
 		if found {
 			s.AddLeavePresence(presence)
+			// a kick or a requested leave frees the seat at the end of the round, otherwise
+			// the seat is held and the hands auto-played until the player is back
+			if !s.IsKicked(presence.GetUserId()) {
+				m.broadcastJson(logger, dispatcher, entity.OpCodeUpdateDisconnected,
+					s.MarkDisconnected(presence.GetUserId()), nil, true)
+			}
 		} else {
+			s.PopKickReason(presence.GetUserId())
 			userIdsLeave = append(userIdsLeave, presence.GetUserId())
 			s.RemovePresences(presence)
 			// cgbdb.UpdateUsersPlayingInMatch(ctx, logger, db, []string{presence.GetUserId()}, "")
@@ -173,6 +201,16 @@ func (m *BaseProcessor) ProcessMatchTerminate(ctx context.Context,
 	for _, presence := range s.GetPresences() {
 		userIds = append(userIds, presence.GetUserId())
 	}
+	botUserIds := make([]string, 0)
+	for _, presence := range s.GetBotPresences() {
+		m.closeBotSession(ctx, logger, db, s, presence.GetUserId(), entity.BotExitMatchEnd)
+		botUserIds = append(botUserIds, presence.GetUserId())
+	}
+	m.balanceBotWallets(ctx, logger, nk, db, s, botUserIds...)
+	for _, userId := range botUserIds {
+		entity.BotLoader.FreeBot(userId)
+		entity.BotPool.Release(userId)
+	}
 	m.emitNkEvent(ctx, define.NakEventMatchEnd, nk, s, userIds)
 }
 
@@ -234,12 +272,16 @@ func (m *BaseProcessor) notifyUserChange(ctx context.Context,
 			walletByUser[wallet.UserId] = v
 		}
 	}
+	// players are listed from first base with their seat
+	presences := s.GetPresences()
+	s.SortBySeat(presences)
 	msg := &pb.UpdateTable{
-		Players:        entity.NewListPlayer(s.GetPresences()),
+		Players:        entity.NewListPlayer(presences),
 		PlayingPlayers: entity.NewListPlayer(s.GetPlayingPresences()),
 		LeavePlayers:   entity.NewListPlayer(leavePresences),
 	}
 	for _, player := range msg.Players {
+		player.Seat = int32(s.GetSeat(player.GetId()))
 		w, exist := walletByUser[player.GetId()]
 		if !exist {
 			continue
@@ -247,6 +289,7 @@ func (m *BaseProcessor) notifyUserChange(ctx context.Context,
 		player.Wallet = strconv.FormatInt(w.Chips, 10)
 	}
 	for _, player := range msg.PlayingPlayers {
+		player.Seat = int32(s.GetSeat(player.GetId()))
 		w, exist := walletByUser[player.GetId()]
 		if !exist {
 			continue
@@ -265,6 +308,7 @@ func (m *BaseProcessor) notifyUserChange(ctx context.Context,
 		logger, dispatcher,
 		int64(pb.OpCodeUpdate_OPCODE_USER_IN_TABLE_INFO),
 		msg, nil, nil, true)
+	m.notifySitOut(logger, dispatcher, s, nil)
 }
 
 func (m *BaseProcessor) report(
@@ -283,10 +327,12 @@ func (m *BaseProcessor) report(
 		ChipFee:  totalFee,
 	})
 	for _, b := range balanceResult.Updates {
+		// balance updates carry no bot marker, bot rows are flagged for the report
 		report.AddPlayerData(&pb.PlayerData{
 			UserId:  b.UserId,
 			Chip:    b.AmountChipCurrent,
 			ChipAdd: b.AmountChipAdd,
+			IsBot:   s.IsBot(b.UserId),
 		})
 	}
 	data, status, err := report.Commit(ctx, nk)
@@ -318,6 +364,7 @@ func (p *Processor) AddBotToMatch(ctx context.Context,
 		for _, p := range bJoin {
 			listUserId = append(listUserId, p.GetUserId())
 		}
+		p.ProcessBotsSeated(ctx, logger, nk, db, s, listUserId...)
 		p.emitNkEvent(ctx, define.NakEventMatchJoin, nk, s, listUserId)
 		s.AddPlayingPresences(bJoin...)
 		p.notifyUserChange(ctx, nk, logger, db, dispatcher, s, nil)
@@ -348,10 +395,13 @@ func (p *Processor) RemoveBotFromMatch(ctx context.Context,
 		return nil
 	}
 
+	p.closeBotSession(ctx, logger, db, s, botUserID, entity.BotExitLeave)
 	err, botPresence := s.RemoveBotFromMatch(botUserID)
 	if err != nil {
 		return err
 	}
+	// back in the pool with a wallet fit for the next table
+	p.balanceBotWallets(ctx, logger, nk, db, s, botUserID)
 
 	// Emit leave event
 	listUserId := []string{botUserID}
@@ -0,0 +1,150 @@
+package processor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/nk-nigeria/blackjack-module/cgbdb"
+	"github.com/nk-nigeria/blackjack-module/entity"
+)
+
+// ProcessBotsSeated prepares bots that just sat down: restores their bankroll
+// and brings their wallet into the band of the table stake
+func (m *BaseProcessor) ProcessBotsSeated(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	db *sql.DB,
+	s *entity.MatchState,
+	botUserIds ...string,
+) {
+	m.loadBotBankrolls(ctx, logger, db, s, botUserIds...)
+	m.balanceBotWallets(ctx, logger, nk, db, s, botUserIds...)
+	for _, userId := range botUserIds {
+		if logic := s.GetBotLogic(userId); logic != nil {
+			logger.WithField("user_id", userId).
+				WithField("personality", logic.GetPersonality()).
+				WithField("balance", logic.GetBalance()).
+				Debug("bot seated")
+		}
+	}
+}
+
+// loadBotBankrolls restores the personality and session totals of bots that just sat down
+func (m *BaseProcessor) loadBotBankrolls(ctx context.Context,
+	logger runtime.Logger,
+	db *sql.DB,
+	s *entity.MatchState,
+	botUserIds ...string,
+) {
+	if db == nil || len(botUserIds) == 0 {
+		return
+	}
+	bankrolls, err := cgbdb.GetBotBankrolls(ctx, logger, db, botUserIds...)
+	if err != nil {
+		return
+	}
+	for _, userId := range botUserIds {
+		bankroll, ok := bankrolls[userId]
+		if !ok {
+			continue
+		}
+		state := &entity.BotLogicState{}
+		if err := json.Unmarshal(bankroll.State, state); err != nil {
+			logger.WithField("user_id", userId).WithField("err", err).Warn("invalid bot bankroll state")
+			state = nil
+		}
+		s.RestoreBotBankroll(userId, state, bankroll.Balance, bankroll.RoundsPlayed, bankroll.Sessions)
+	}
+}
+
+// saveBotBankrolls persists the balance and personality state of every seated bot
+func (m *BaseProcessor) saveBotBankrolls(ctx context.Context,
+	logger runtime.Logger,
+	db *sql.DB,
+	s *entity.MatchState,
+) {
+	if db == nil {
+		return
+	}
+	for _, presence := range s.GetBotPresences() {
+		userId := presence.GetUserId()
+		session := s.GetBotSession(userId)
+		if session == nil {
+			continue
+		}
+		cgbdb.UpsertBotBankroll(ctx, logger, db, m.botBankroll(s, userId, session, session.PriorSessions))
+	}
+}
+
+// closeBotSession records the session of a bot leaving the table
+func (m *BaseProcessor) closeBotSession(ctx context.Context,
+	logger runtime.Logger,
+	db *sql.DB,
+	s *entity.MatchState,
+	userId string,
+	reason string,
+) {
+	session := s.GetBotSession(userId)
+	if session == nil {
+		return
+	}
+	if session.ExitReason == "" {
+		session.ExitReason = reason
+	}
+	bankroll := m.botBankroll(s, userId, session, session.PriorSessions+1)
+	s.EndBotSession(userId)
+	if db == nil {
+		return
+	}
+	cgbdb.InsertBotSession(ctx, logger, db, &cgbdb.BotSession{
+		UserId:       userId,
+		MatchId:      s.GetMatchID(),
+		Personality:  bankroll.Personality,
+		BalanceStart: session.StartBalance,
+		BalanceEnd:   bankroll.Balance,
+		Rounds:       session.Rounds,
+		ExitReason:   session.ExitReason,
+		StartTime:    session.StartTime,
+		EndTime:      time.Now(),
+	})
+	cgbdb.UpsertBotBankroll(ctx, logger, db, bankroll)
+	logger.WithField("user_id", userId).
+		WithField("reason", session.ExitReason).
+		WithField("balance_start", session.StartBalance).
+		WithField("balance_end", bankroll.Balance).
+		Info("bot session closed")
+}
+
+func (m *BaseProcessor) botBankroll(s *entity.MatchState, userId string, session *entity.BotSession, sessions int64) *cgbdb.BotBankroll {
+	bankroll := &cgbdb.BotBankroll{
+		UserId:       userId,
+		RoundsPlayed: session.PriorRounds + session.Rounds,
+		Sessions:     sessions,
+	}
+	if logic := s.GetBotLogic(userId); logic != nil {
+		state := logic.ExportState()
+		bankroll.Personality = string(state.Personality)
+		bankroll.Balance = logic.GetBalance()
+		bankroll.State, _ = json.Marshal(state)
+	}
+	return bankroll
+}
+
+// ProcessBotRetire makes bots that are busted or up enough leave the table
+func (p *Processor) ProcessBotRetire(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	db *sql.DB,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+) {
+	for userId, reason := range s.BotsToRetire() {
+		logger.WithField("user_id", userId).WithField("reason", reason).Info("bot leaves the table")
+		if err := p.RemoveBotFromMatch(ctx, logger, nk, db, dispatcher, s, userId); err != nil {
+			logger.WithField("user_id", userId).WithField("err", err).Error("remove retiring bot failed")
+		}
+	}
+}
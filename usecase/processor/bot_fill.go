@@ -0,0 +1,57 @@
+package processor
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/nk-nigeria/blackjack-module/entity"
+)
+
+// ProcessBotFill applies the bot fill policy of the table stake, phase names the game
+// state it runs in for the decision log
+func (p *Processor) ProcessBotFill(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	db *sql.DB,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	phase string,
+) {
+	markUnit := int64(s.Label.MarkUnit)
+	decision := s.BotFillPolicy().Evaluate(s.BotFillInput())
+	decision.MatchId = s.GetMatchID()
+	decision.MarkUnit = markUnit
+	decision.Phase = phase
+	decision.Time = time.Now()
+	entity.BotFillLog.Add(decision)
+	logger.WithField("match_id", decision.MatchId).
+		WithField("phase", phase).
+		WithField("humans", decision.Humans).
+		WithField("bots", decision.Bots).
+		WithField("add", decision.Add).
+		WithField("remove", decision.Remove).
+		WithField("reason", decision.Reason).
+		Info("bot fill decision")
+
+	if decision.Add > 0 {
+		if err := p.AddBotToMatch(ctx, logger, nk, db, dispatcher, s, decision.Add); err != nil {
+			logger.WithField("err", err).Error("bot fill add bots failed")
+		}
+	}
+	// last seated bots leave first
+	for i := 0; i < decision.Remove && len(s.Bots) > 0; i++ {
+		userId := s.Bots[len(s.Bots)-1].GetUserId()
+		if err := p.RemoveBotFromMatch(ctx, logger, nk, db, dispatcher, s, userId); err != nil {
+			logger.WithField("user_id", userId).WithField("err", err).Error("bot fill remove bot failed")
+			break
+		}
+	}
+	if decision.Reason == entity.BotFillReasonHumanWaiting {
+		s.ClearHumanWaiting()
+	}
+	if decision.Remove > 0 {
+		p.promoteSeatQueue(ctx, logger, nk, db, dispatcher, s)
+	}
+}
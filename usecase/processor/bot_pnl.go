@@ -0,0 +1,36 @@
+package processor
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/nk-nigeria/blackjack-module/cgbdb"
+	"github.com/nk-nigeria/blackjack-module/entity"
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+// recordBotPnl adds the bot results of the round to the daily bot pnl of the table stake
+func (m *BaseProcessor) recordBotPnl(ctx context.Context,
+	logger runtime.Logger,
+	db *sql.DB,
+	s *entity.MatchState,
+	balanceResult *pb.BalanceResult,
+) {
+	if db == nil {
+		return
+	}
+	pnl := s.BotRoundPnl(balanceResult)
+	if pnl.Rounds == 0 {
+		return
+	}
+	cgbdb.AddBotPnlDaily(ctx, logger, db, &cgbdb.BotPnlDaily{
+		Day:      time.Now(),
+		MarkUnit: int64(s.Label.MarkUnit),
+		Rounds:   pnl.Rounds,
+		ChipBet:  pnl.Bet,
+		ChipWin:  pnl.Win,
+		ChipNet:  pnl.Net,
+	})
+}
@@ -0,0 +1,80 @@
+package processor
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/nk-nigeria/blackjack-module/cgbdb"
+	"github.com/nk-nigeria/blackjack-module/entity"
+)
+
+// balanceBotWallets tops up or trims bot wallets to the band of the table stake.
+// Adjustments are house funded and recorded apart from player flow.
+func (m *BaseProcessor) balanceBotWallets(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	db *sql.DB,
+	s *entity.MatchState,
+	botUserIds ...string,
+) {
+	if nk == nil || len(botUserIds) == 0 {
+		return
+	}
+	markUnit := int64(s.Label.MarkUnit)
+	band := entity.BotWalletBandForStake(markUnit)
+	wallets, err := entity.ReadWalletUsers(ctx, nk, logger, botUserIds...)
+	if err != nil {
+		return
+	}
+	for _, wallet := range wallets {
+		amount := band.Adjustment(wallet.Chips)
+		if amount == 0 {
+			continue
+		}
+		action := entity.WalletActionBotTopUp
+		if amount < 0 {
+			action = entity.WalletActionBotTrim
+		}
+		metadata := map[string]interface{}{
+			"action":    string(action),
+			"category":  entity.WalletLedgerHouseBot,
+			"game":      entity.ModuleName,
+			"match_id":  s.GetMatchID(),
+			"mark_unit": markUnit,
+		}
+		updated, previous, err := nk.WalletUpdate(ctx, wallet.UserId, map[string]int64{"chips": amount}, metadata, true)
+		if err != nil {
+			logger.WithField("user_id", wallet.UserId).
+				WithField("amount", amount).
+				WithField("err", err).
+				Error("bot-wallet-adjust-error")
+			continue
+		}
+		logger.WithField("user_id", wallet.UserId).
+			WithField("action", action).
+			WithField("amount", amount).
+			WithField("balance", updated["chips"]).
+			Info("bot wallet adjusted")
+		s.ApplyBotWalletAdjustment(wallet.UserId, updated["chips"])
+		if db != nil {
+			cgbdb.InsertBotWalletLedger(ctx, logger, db, &cgbdb.BotWalletLedger{
+				UserId:        wallet.UserId,
+				MatchId:       s.GetMatchID(),
+				MarkUnit:      markUnit,
+				Action:        string(action),
+				Amount:        amount,
+				BalanceBefore: previous["chips"],
+				BalanceAfter:  updated["chips"],
+			})
+			pnl := &cgbdb.BotPnlDaily{Day: time.Now(), MarkUnit: markUnit}
+			if amount > 0 {
+				pnl.ChipTopUp = amount
+			} else {
+				pnl.ChipTrim = -amount
+			}
+			cgbdb.AddBotPnlDaily(ctx, logger, db, pnl)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package processor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/nk-nigeria/blackjack-module/cgbdb"
+	"github.com/nk-nigeria/blackjack-module/entity"
+)
+
+// broadcastJson sends a JSON payload for opcodes outside the shared proto
+func (m *BaseProcessor) broadcastJson(logger runtime.Logger,
+	dispatcher runtime.MatchDispatcher,
+	opCode int64,
+	data interface{},
+	presences []runtime.Presence,
+	reliable bool,
+) error {
+	dataJson, err := json.Marshal(data)
+	if err != nil {
+		logger.Error("Error when marshal json data for broadcastJson")
+		return err
+	}
+	if err := dispatcher.BroadcastMessage(opCode, dataJson, presences, nil, reliable); err != nil {
+		logger.Error("Error BroadcastMessage, message: %s", string(dataJson))
+		return err
+	}
+	return nil
+}
+
+// processChatMessage handles the chat, emote, mute and report requests of a player
+func (m *BaseProcessor) processChatMessage(ctx context.Context,
+	logger runtime.Logger,
+	db *sql.DB,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	message runtime.MatchData,
+) {
+	userId := message.GetUserId()
+	chat := s.Chat()
+	switch message.GetOpCode() {
+	case entity.OpCodeRequestChat:
+		req := &entity.ChatRequest{}
+		if err := json.Unmarshal(message.GetData(), req); err != nil {
+			logger.WithField("user-id", userId).WithField("error", err).Error("error-parse-chat-request")
+			return
+		}
+		msg, code := chat.NewChat(userId, req.Text)
+		if code != "" {
+			m.notifyChatError(logger, dispatcher, message, code)
+			return
+		}
+		m.broadcastJson(logger, dispatcher, entity.OpCodeUpdateChat, msg, m.chatRecipients(s, userId), true)
+	case entity.OpCodeRequestEmote:
+		req := &entity.EmoteRequest{}
+		if err := json.Unmarshal(message.GetData(), req); err != nil {
+			logger.WithField("user-id", userId).WithField("error", err).Error("error-parse-emote-request")
+			return
+		}
+		msg, code := chat.NewEmote(userId, req.Emote)
+		if code != "" {
+			m.notifyChatError(logger, dispatcher, message, code)
+			return
+		}
+		m.broadcastJson(logger, dispatcher, entity.OpCodeUpdateEmote, msg, m.chatRecipients(s, userId), false)
+	case entity.OpCodeRequestMute:
+		req := &entity.MuteRequest{}
+		if err := json.Unmarshal(message.GetData(), req); err != nil {
+			logger.WithField("user-id", userId).WithField("error", err).Error("error-parse-mute-request")
+			return
+		}
+		if req.UserId == "" || req.UserId == userId {
+			m.notifyChatError(logger, dispatcher, message, entity.ChatErrorUnknownUser)
+			return
+		}
+		chat.SetMute(userId, req.UserId, req.Mute)
+	case entity.OpCodeRequestReport:
+		req := &entity.ReportRequest{}
+		if err := json.Unmarshal(message.GetData(), req); err != nil {
+			logger.WithField("user-id", userId).WithField("error", err).Error("error-parse-report-request")
+			return
+		}
+		if s.GetPresenceOrSpectator(req.UserId) == nil || req.UserId == userId {
+			m.notifyChatError(logger, dispatcher, message, entity.ChatErrorUnknownUser)
+			return
+		}
+		// a reported player is muted for the reporter right away
+		chat.SetMute(userId, req.UserId, true)
+		logger.WithField("reporter", userId).
+			WithField("reported", req.UserId).
+			WithField("reason", req.Reason).
+			Info("chat report")
+		if db == nil {
+			return
+		}
+		history, _ := json.Marshal(chat.History(req.UserId, entity.ChatReportContext))
+		cgbdb.InsertChatReport(ctx, logger, db, &cgbdb.ChatReport{
+			ReporterId: userId,
+			ReportedId: req.UserId,
+			MatchId:    s.GetMatchID(),
+			Reason:     req.Reason,
+			Messages:   string(history),
+		})
+	}
+}
+
+// chatRecipients returns the human players and spectators that didn't mute sender, nil when nobody did
+func (m *BaseProcessor) chatRecipients(s *entity.MatchState, sender string) []runtime.Presence {
+	recipients := make([]runtime.Presence, 0)
+	muted := false
+	for _, presence := range append(s.GetPresences(), s.GetSpectators()...) {
+		if s.IsBot(presence.GetUserId()) {
+			continue
+		}
+		if s.Chat().IsMuted(presence.GetUserId(), sender) {
+			muted = true
+			continue
+		}
+		recipients = append(recipients, presence)
+	}
+	if !muted {
+		return nil
+	}
+	return recipients
+}
+
+func (m *BaseProcessor) notifyChatError(logger runtime.Logger,
+	dispatcher runtime.MatchDispatcher,
+	message runtime.MatchData,
+	code string,
+) {
+	m.broadcastJson(logger, dispatcher, entity.OpCodeUpdateChatError,
+		&entity.ChatError{Code: code}, []runtime.Presence{message}, true)
+}
+
+// notifyBotReactions lets bots react to the big wins and busts of the round
+func (m *BaseProcessor) notifyBotReactions(logger runtime.Logger,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+) {
+	for _, msg := range s.BotReactions(s.GetBalanceResult()) {
+		opCode := int64(entity.OpCodeUpdateChat)
+		if msg.Emote != "" {
+			opCode = entity.OpCodeUpdateEmote
+		}
+		m.broadcastJson(logger, dispatcher, opCode, msg, m.chatRecipients(s, msg.UserId), false)
+	}
+}
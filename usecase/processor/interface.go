@@ -55,5 +55,48 @@ type IProcessor interface {
 		s *entity.MatchState,
 		botUserID string) error
 
+	ProcessBotsSeated(ctx context.Context,
+		logger runtime.Logger,
+		nk runtime.NakamaModule,
+		db *sql.DB,
+		s *entity.MatchState,
+		botUserIds ...string)
+
+	ProcessBotRetire(ctx context.Context,
+		logger runtime.Logger,
+		nk runtime.NakamaModule,
+		db *sql.DB,
+		dispatcher runtime.MatchDispatcher,
+		s *entity.MatchState)
+
+	ProcessPropBetRefund(ctx context.Context,
+		logger runtime.Logger,
+		nk runtime.NakamaModule,
+		dispatcher runtime.MatchDispatcher,
+		s *entity.MatchState)
+
+	ProcessBotFill(ctx context.Context,
+		logger runtime.Logger,
+		nk runtime.NakamaModule,
+		db *sql.DB,
+		dispatcher runtime.MatchDispatcher,
+		s *entity.MatchState,
+		phase string)
+
+	ProcessMatchSignal(ctx context.Context,
+		logger runtime.Logger,
+		nk runtime.NakamaModule,
+		db *sql.DB,
+		dispatcher runtime.MatchDispatcher,
+		s *entity.MatchState,
+		data string) *entity.MatchSignalReply
+
+	ProcessMatchClose(ctx context.Context,
+		logger runtime.Logger,
+		nk runtime.NakamaModule,
+		db *sql.DB,
+		dispatcher runtime.MatchDispatcher,
+		s *entity.MatchState)
+
 	IBaseProcessor
 }
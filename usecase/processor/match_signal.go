@@ -0,0 +1,345 @@
+package processor
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/nk-nigeria/blackjack-module/cgbdb"
+	"github.com/nk-nigeria/blackjack-module/entity"
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+// ProcessMatchSignal applies a command signalled by an operator, every signal is audited
+// whether it was applied or refused
+func (p *Processor) ProcessMatchSignal(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	db *sql.DB,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	data string,
+) *entity.MatchSignalReply {
+	reply := &entity.MatchSignalReply{}
+	now := time.Now()
+	sig, err := entity.ParseMatchSignal(data)
+	if err == nil {
+		reply.Command = sig.Command
+		err = sig.Verify(s.GetMatchID(), now)
+	}
+	if err == nil && !s.AcceptSignalNonce(sig, now) {
+		err = entity.ErrSignalReplayed
+	}
+	if err == nil {
+		err = p.applyMatchSignal(ctx, logger, nk, db, dispatcher, s, sig, reply)
+	}
+	reply.Ok = err == nil
+	if err != nil {
+		reply.Error = err.Error()
+	}
+	reply.Paused, reply.Closing = s.IsPaused(), s.IsClosing()
+
+	audit := &cgbdb.MatchSignalAudit{
+		MatchId:  s.GetMatchID(),
+		Command:  reply.Command,
+		Ok:       reply.Ok,
+		Error:    reply.Error,
+		Refunded: reply.Refunded,
+	}
+	if sig != nil {
+		audit.OperatorId, audit.Reason, audit.UserId = sig.OperatorId, sig.Reason, sig.UserId
+		audit.Params = map[string]any{"nonce": sig.Nonce}
+		if sig.Count != 0 {
+			audit.Params["count"] = sig.Count
+		}
+		if sig.HoleCard {
+			audit.Params["hole_card"] = true
+		}
+		if sig.Command == entity.SignalSetLimits {
+			audit.Params["min_bet"], audit.Params["max_bet"] = sig.MinBet, sig.MaxBet
+		}
+	}
+	logger.WithField("operator", audit.OperatorId).
+		WithField("command", audit.Command).
+		WithField("user_id", audit.UserId).
+		WithField("params", audit.Params).
+		WithField("reason", audit.Reason).
+		WithField("ok", audit.Ok).
+		WithField("error", audit.Error).
+		WithField("refunded", audit.Refunded).
+		Info("match signal")
+	if db != nil {
+		if err := cgbdb.InsertMatchSignalAudit(ctx, logger, db, audit); err != nil {
+			// the command stands, the operator is told it left no audit row
+			logger.WithField("operator", audit.OperatorId).
+				WithField("command", audit.Command).
+				WithField("err", err).
+				Error("match signal not audited")
+			reply.AuditError = true
+		}
+	}
+	return reply
+}
+
+func (p *Processor) applyMatchSignal(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	db *sql.DB,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	sig *entity.MatchSignal,
+	reply *entity.MatchSignalReply,
+) error {
+	switch sig.Command {
+	case entity.SignalPause, entity.SignalResume, entity.SignalClose:
+		if s.IsClosing() {
+			return entity.ErrSignalClosing
+		}
+		switch sig.Command {
+		case entity.SignalPause:
+			s.SetPaused(true, sig.Reason)
+		case entity.SignalResume:
+			s.SetPaused(false, sig.Reason)
+		default:
+			s.SetClosing(sig.Reason)
+		}
+		p.notifyTableStatus(logger, dispatcher, s)
+		return nil
+	case entity.SignalRefund:
+		switch s.GetGameState() {
+		case pb.GameState_GAME_STATE_PREPARING, pb.GameState_GAME_STATE_PLAY:
+		case pb.GameState_GAME_STATE_REWARD:
+			return entity.ErrSignalSettled
+		default:
+			return entity.ErrSignalNoRound
+		}
+		reply.Refunded = p.refundRound(ctx, logger, nk, dispatcher, s)
+		return nil
+	case entity.SignalInspect:
+		reply.State = s.Dump(sig.HoleCard)
+		return nil
+	case entity.SignalKick:
+		if s.IsBot(sig.UserId) {
+			return p.removeBotByOperator(ctx, logger, nk, db, dispatcher, s, sig.UserId)
+		}
+		presence := s.GetPresenceOrSpectator(sig.UserId)
+		if presence == nil {
+			return entity.ErrSignalNotFound
+		}
+		reply.Refunded = p.kickByOperator(ctx, logger, nk, db, dispatcher, s, presence)
+		return nil
+	case entity.SignalAddBots:
+		if sig.Count <= 0 {
+			return entity.ErrSignalBadArgument
+		}
+		if isRoundInPlay(s) {
+			return entity.ErrSignalInRound
+		}
+		if err := p.AddBotToMatch(ctx, logger, nk, db, dispatcher, s, sig.Count); err != nil {
+			return entity.ErrSignalNoBot
+		}
+		return nil
+	case entity.SignalRemoveBot:
+		if !s.IsBot(sig.UserId) {
+			return entity.ErrSignalNotFound
+		}
+		return p.removeBotByOperator(ctx, logger, nk, db, dispatcher, s, sig.UserId)
+	case entity.SignalSetLimits:
+		limits := entity.TableLimits{MinBet: sig.MinBet, MaxBet: sig.MaxBet}
+		if limits.Validate() != nil {
+			return entity.ErrSignalBadArgument
+		}
+		s.SetTableLimits(limits)
+		reply.Limits = s.GetTableLimits()
+		p.broadcastJson(logger, dispatcher, entity.OpCodeUpdateTableLimits,
+			&entity.TableLimitsUpdate{Limits: s.GetTableLimits()}, nil, true)
+		return nil
+	}
+	return entity.ErrSignalUnknown
+}
+
+// isRoundInPlay reports whether cards are out, seats of bots only change between rounds
+func isRoundInPlay(s *entity.MatchState) bool {
+	switch s.GetGameState() {
+	case pb.GameState_GAME_STATE_PLAY, pb.GameState_GAME_STATE_REWARD:
+		return true
+	}
+	return false
+}
+
+// removeBotByOperator takes a bot off the table between rounds, a bot that bet stays for the round
+func (p *Processor) removeBotByOperator(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	db *sql.DB,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	botUserId string,
+) error {
+	if isRoundInPlay(s) || s.IsBet(botUserId) {
+		return entity.ErrSignalInRound
+	}
+	if err := p.RemoveBotFromMatch(ctx, logger, nk, db, dispatcher, s, botUserId); err != nil {
+		return entity.ErrSignalNoBot
+	}
+	return nil
+}
+
+// refundRound gives back the chips charged for the main and prop bets of the round and
+// ends it unsettled, it returns the chips given back
+func (p *Processor) refundRound(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+) int64 {
+	refunded := int64(0)
+	for _, userId := range s.GetPropBettors() {
+		for _, bet := range s.GetPropBets(userId) {
+			refunded += bet.Chips
+		}
+	}
+	p.ProcessPropBetRefund(ctx, logger, nk, dispatcher, s)
+
+	refunds := s.RefundRoundBets()
+	if s.GetGameState() == pb.GameState_GAME_STATE_PLAY {
+		// drop the deals still to show, the round moves on to the reward without a settlement
+		s.Timeline().Start(time.Now())
+		s.SetIsGameEnded(true)
+	}
+	refunded += p.refundStakes(ctx, logger, nk, dispatcher, refunds)
+	logger.WithField("users", len(refunds)).Info("refund bets of a round cancelled by an operator")
+	return refunded
+}
+
+// refundStakes pays back the chips charged to each wallet for the round, clears the chips
+// of the players off the table and returns the chips given back
+func (p *Processor) refundStakes(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	dispatcher runtime.MatchDispatcher,
+	refunds map[string]int64,
+) int64 {
+	if len(refunds) == 0 {
+		return 0
+	}
+	userIds := make([]string, 0, len(refunds))
+	for userId := range refunds {
+		userIds = append(userIds, userId)
+	}
+	chips := make(map[string]int64, len(userIds))
+	if wallets, err := entity.ReadWalletUsers(ctx, nk, logger, userIds...); err == nil {
+		for _, w := range wallets {
+			chips[w.UserId] = w.Chips
+		}
+	}
+	refunded := int64(0)
+	balanceResult := &pb.BalanceResult{}
+	for _, userId := range userIds {
+		refunded += refunds[userId]
+		balanceResult.Updates = append(balanceResult.Updates, &pb.BalanceUpdate{
+			UserId:            userId,
+			AmountChipBefore:  chips[userId],
+			AmountChipAdd:     refunds[userId],
+			AmountChipCurrent: chips[userId] + refunds[userId],
+		})
+	}
+	// refunds do not farm VIP points, the db is not needed
+	if err := p.updateChipByResultGameFinish(ctx, nk, logger, nil, balanceResult, entity.WalletActionRoundRefund); err != nil {
+		return 0
+	}
+	for _, userId := range userIds {
+		// clear the chips of the player off the table
+		p.broadcastMessage(
+			logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_TABLE),
+			&pb.BlackjackUpdateDesk{
+				IsUpdateBet: true,
+				Bet:         &pb.BlackjackPlayerBet{UserId: userId},
+			}, nil, nil, true,
+		)
+	}
+	p.broadcastMessage(
+		logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_WALLET),
+		balanceResult, nil, nil, true,
+	)
+	return refunded
+}
+
+// kickByOperator takes a player or a spectator off the table for the rest of the match and
+// returns the chips given back. The bet of a round not dealt yet is refunded, the hands
+// already dealt stand and are settled with the round like those of any absent player.
+func (p *Processor) kickByOperator(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	db *sql.DB,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	presence runtime.Presence,
+) int64 {
+	userId := presence.GetUserId()
+	s.BanUser(userId)
+	s.SetKickReason(userId, entity.KickReasonOperator)
+	p.notifyKickReason(logger, dispatcher, entity.KickReasonOperator, presence)
+	p.broadcastMessage(
+		logger, dispatcher,
+		int64(pb.OpCodeUpdate_OPCODE_KICK_OFF_THE_TABLE),
+		nil, []runtime.Presence{presence}, nil, true,
+	)
+	refunded := int64(0)
+	if _, dealt := s.PlayingPresences.Get(userId); dealt && isRoundInPlay(s) {
+		p.standHands(ctx, logger, nk, db, dispatcher, s, userId)
+	} else if chips := s.DropRoundBet(userId); chips > 0 {
+		refunded = p.refundStakes(ctx, logger, nk, dispatcher, map[string]int64{userId: chips})
+	}
+	s.RemoveLeavePresence(userId)
+	dispatcher.MatchKick([]runtime.Presence{presence})
+	if s.IsSpectator(userId) {
+		return refunded
+	}
+	s.RemovePresences(presence)
+	p.notifyUserChange(ctx, nk, logger, db, dispatcher, s, []runtime.Presence{presence})
+	return refunded
+}
+
+// standHands stands the hands userId has left to play so the round goes on without it
+func (p *Processor) standHands(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	db *sql.DB,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	userId string,
+) {
+	// a split takes a stand on each hand
+	for i := 0; i < 2 && s.IsInTurn(userId) && len(s.GetLegalActionsByUserId(userId)) > 0; i++ {
+		p.applyAction(ctx, logger, nk, db, dispatcher, s,
+			&pb.BlackjackAction{UserId: userId, Code: pb.BlackjackActionCode_BLACKJACK_ACTION_STAY}, 0)
+	}
+}
+
+// notifyTableStatus tells everyone at the table it is paused, resumed or closing
+func (p *Processor) notifyTableStatus(logger runtime.Logger,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+) {
+	status := s.GetTableStatus()
+	if status == nil {
+		status = &entity.TableStatus{}
+	}
+	p.broadcastJson(logger, dispatcher, entity.OpCodeUpdateTableStatus, status, nil, true)
+}
+
+// ProcessMatchClose tells everyone a match closed by an operator is over and refunds the
+// prop bets left, the match is terminated by the caller
+func (p *Processor) ProcessMatchClose(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	db *sql.DB,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+) {
+	presences := append(s.GetPresences(), s.GetSpectators()...)
+	p.notifyKickReason(logger, dispatcher, entity.KickReasonTableClosed, presences...)
+	p.ProcessPropBetRefund(ctx, logger, nk, dispatcher, s)
+}
@@ -61,6 +61,7 @@ func (p *Processor) ProcessNewGame(
 	// deal
 	for _, presence := range s.GetPlayingPresences() {
 		s.ResetUserNotInteract(presence.GetUserId())
+		s.CountTimeBankRound(presence.GetUserId())
 		listPlayerId = append(listPlayerId, presence.GetUserId())
 		s.AddCards(p.engine.Deal(2), presence.GetUserId(), pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
 	}
@@ -78,14 +79,24 @@ func (p *Processor) ProcessNewGame(
 	// }
 	s.AddCards(p.engine.Deal(2), "", pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
 	p.notifyUserChange(ctx, nk, logger, db, dispatcher, s, nil)
-	p.notifyInitialDealCard(
+	// cards are paced over the next ticks, turns start once clients showed them
+	timeline := s.Timeline()
+	timeline.Start(time.Now())
+	dealEnd := p.notifyInitialDealCard(
 		ctx, nk, logger, dispatcher, s,
 	)
 	if p.turnBaseEngine == nil {
 		p.turnBaseEngine = NewTurnBaseEngine()
 	}
-	time.Sleep(2500 * time.Millisecond)
+	timeline.Schedule(entity.TimelineStepTurns, "", dealEnd+entity.DealSettle, func() {
+		p.startTurns(s, listPlayerId)
+	})
+	p.broadcastJson(logger, dispatcher, entity.OpCodeUpdateTimeline, timeline.Update(), nil, true)
+}
 
+// startTurns starts the insurance round then the turns of the players, timed by the table config
+func (p *Processor) startTurns(s *entity.MatchState, listPlayerId []string) {
+	cfg := s.TableConfig()
 	p.turnBaseEngine.Config(
 		listPlayerId,
 		[]*Round{
@@ -95,17 +106,17 @@ func (p *Processor) ProcessNewGame(
 				phases: []*Phase{
 					{
 						code:     "main",
-						duration: time.Second * 5,
+						duration: cfg.InsuranceDuration(),
 					},
 				},
 			},
 			{
 				code:   "playing",
-				isGlob: false,
+				isGlob: cfg.Simultaneous,
 				phases: []*Phase{
 					{
 						code:     "main",
-						duration: time.Second * 10,
+						duration: cfg.TurnDuration(),
 					},
 				},
 			},
@@ -124,12 +135,17 @@ func (p *Processor) ProcessFinishGame(ctx context.Context,
 	s *entity.MatchState,
 ) {
 
-	p.revealDealerHiddenCard(ctx, nk, logger, dispatcher, s)
+	// the dealer plays at once, clients are shown each step on the timeline
+	reveal := p.revealDealerHiddenCardMessage(s)
+	draws := make([]*pb.BlackjackUpdateDeal, 0)
 	for s.IsDealerMustDraw() {
 		cards := p.engine.Deal(1)
 		s.AddCards(cards, "", pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
-		p.notifyDealCard(ctx, nk, logger, dispatcher, s, "", pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
+		draws = append(draws, p.dealCardMessage(s, "", pb.BlackjackHandN0_BLACKJACK_HAND_1ST))
 	}
+	offset := p.scheduleDealerSequence(logger, dispatcher, s, reveal, draws)
+	// every card is face up now, let counting bots update their count
+	s.ObserveRoundCards(p.engine.CardsRemaining())
 	s.SetUpdateFinish(s.CalcGameFinish())
 
 	updateFinish := s.GetUpdateFinish()
@@ -137,16 +153,36 @@ func (p *Processor) ProcessFinishGame(ctx context.Context,
 		ctx, nk, logger, db, dispatcher, s, updateFinish,
 	)
 	s.SetBalanceResult(balanceResult)
-	p.updateChipByResultGameFinish(ctx, nk, logger, db, balanceResult)
-	p.broadcastMessage(
-		logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_FINISH),
-		updateFinish, nil, nil, true,
-	)
-	p.broadcastMessage(
-		logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_WALLET),
-		balanceResult, nil, nil, true,
-	)
-	p.report(ctx, logger, nk, balanceResult, totalFee, s)
+	s.RecordBotResults(balanceResult)
+	p.saveBotBankrolls(ctx, logger, db, s)
+	p.recordBotPnl(ctx, logger, db, s, balanceResult)
+	p.updateChipByResultGameFinish(ctx, nk, logger, db, balanceResult, "")
+	p.notifyAbsentSettlements(ctx, logger, nk, s, balanceResult)
+	propUpdates, propResults := p.settlePropBets(ctx, logger, nk, s)
+	// results are shown once the dealer total is
+	timeline := s.Timeline()
+	timeline.Schedule(entity.TimelineStepSettle, "", offset, func() {
+		p.broadcastMessage(
+			logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_FINISH),
+			updateFinish, nil, nil, true,
+		)
+		p.broadcastMessage(
+			logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_WALLET),
+			balanceResult, nil, nil, true,
+		)
+		p.notifyPropBetResults(logger, dispatcher, s, propResults)
+		p.notifyBotReactions(logger, dispatcher, s)
+	})
+	p.broadcastJson(logger, dispatcher, entity.OpCodeUpdateTimeline, timeline.Update(), nil, true)
+	// the reward phase lasts until clients saw the whole dealer play
+	s.SetUpCountDown(entity.GameStateDuration[pb.GameState_GAME_STATE_REWARD] + offset)
+	// prop bets are reported with the round they were settled on
+	reportResult := &pb.BalanceResult{}
+	if balanceResult != nil {
+		reportResult.Updates = append(reportResult.Updates, balanceResult.Updates...)
+	}
+	reportResult.Updates = append(reportResult.Updates, propUpdates...)
+	p.report(ctx, logger, nk, reportResult, totalFee, s)
 }
 
 func (p *Processor) ProcessTurnbase(ctx context.Context,
@@ -156,10 +192,19 @@ func (p *Processor) ProcessTurnbase(ctx context.Context,
 	dispatcher runtime.MatchDispatcher,
 	s *entity.MatchState,
 ) {
+	// wait for the deal to be shown before turns start
+	if !s.Timeline().Done() {
+		return
+	}
+	p.extendTurnFromTimeBank(logger, dispatcher, s)
+	p.applyTimeoutAction(ctx, logger, nk, db, dispatcher, s)
 	var turnInfo *TurnInfo
 	if p.turnBaseEngine != nil {
 		turnInfo = p.turnBaseEngine.Loop()
 	}
+	if turnInfo == nil {
+		return
+	}
 	if turnInfo.isNewRound {
 		s.SetCurrentTurn(turnInfo.userId)
 		switch turnInfo.roundCode {
@@ -211,8 +256,16 @@ func (p *Processor) ProcessTurnbase(ctx context.Context,
 			s.SetAllowBet(false)
 			s.SetAllowInsurance(false)
 			s.SetAllowAction(true)
+			if s.TableConfig().Simultaneous {
+				p.startDecisions(logger, dispatcher, s)
+				return
+			}
 		}
 	}
+	if s.IsSimultaneousRound() {
+		p.processDecisions(ctx, logger, nk, db, dispatcher, s)
+		return
+	}
 	if turnInfo.isNewTurn && turnInfo.roundCode == "playing" {
 		if s.IsAllVisited() {
 			s.SetIsGameEnded(true)
@@ -222,6 +275,11 @@ func (p *Processor) ProcessTurnbase(ctx context.Context,
 	if turnInfo.isNewPhase && turnInfo.roundCode == "playing" {
 		s.SetVisited(turnInfo.userId)
 		s.SetCurrentTurn(turnInfo.userId)
+		if s.IsBanned(turnInfo.userId) {
+			// kicked by an operator, its hands stand
+			p.standHands(ctx, logger, nk, db, dispatcher, s, turnInfo.userId)
+			return
+		}
 		if len(s.GetLegalActions()) == 0 {
 			switch s.GetCurrentHandN0(turnInfo.userId) {
 			case pb.BlackjackHandN0_BLACKJACK_HAND_1ST:
@@ -251,6 +309,10 @@ func (p *Processor) ProcessMessageFromUser(
 	s *entity.MatchState,
 ) {
 	for _, message := range messages {
+		// spectators watch, they can't bet or act at the table
+		if s.IsSpectator(message.GetUserId()) && !entity.IsSpectatorOpCode(message.GetOpCode()) {
+			continue
+		}
 		lib.HandlerTipInGameEvent(ctx, nk, logger, dispatcher, message)
 		switch pb.OpCodeRequest(message.GetOpCode()) {
 		case pb.OpCodeRequest_OPCODE_REQUEST_BET:
@@ -269,11 +331,24 @@ func (p *Processor) ProcessMessageFromUser(
 			}
 			bet.UserId = message.GetUserId()
 			s.ResetUserNotInteract(bet.UserId)
+			// betting brings a player sitting out back to the table
+			if s.SitIn(bet.UserId) {
+				p.notifySitOut(logger, dispatcher, s, nil)
+			}
 			wallet, err := entity.ReadWalletUser(ctx, nk, logger, bet.UserId)
 			if err != nil {
 				logger.Error("error.read-user-wallet")
 				continue
 			}
+			// bets outside the limits set by operators are refused
+			if total := s.MainBetAfter(bet.UserId, bet.Code, bet.Chips); !s.IsWithinTableLimits(total) {
+				if presence := s.GetPresence(bet.UserId); presence != nil && !s.IsBot(bet.UserId) {
+					p.broadcastJson(logger, dispatcher, entity.OpCodeUpdateTableLimits,
+						&entity.TableLimitsUpdate{Limits: s.GetTableLimits(), Rejected: total},
+						[]runtime.Presence{presence}, true)
+				}
+				continue
+			}
 			// s.ResetUserNotInteract(bet.UserId)
 			switch bet.Code {
 			case pb.BlackjackBetCode_BLACKJACK_BET_DOUBLE:
@@ -301,11 +376,11 @@ func (p *Processor) ProcessMessageFromUser(
 				}
 			}
 		case pb.OpCodeRequest_OPCODE_REQUEST_DECLARE_CARDS:
-			if s.GetGameState() != pb.GameState_GAME_STATE_PLAY || s.GetCurrentTurn() == "" {
+			if s.GetGameState() != pb.GameState_GAME_STATE_PLAY || (s.GetCurrentTurn() == "" && !s.IsSimultaneousRound()) {
 				logger.WithField("user-id", message.GetUserId()).Error("current turn is empty")
 				continue
 			}
-			if s.GetCurrentTurn() != message.GetUserId() {
+			if !s.IsInTurn(message.GetUserId()) {
 				logger.WithField("user-id", message.GetUserId()).WithField("current-turn", s.GetCurrentTurn()).Error("current turn is not match")
 				continue
 			}
@@ -321,110 +396,9 @@ func (p *Processor) ProcessMessageFromUser(
 				continue
 			}
 			action.UserId = message.GetUserId()
-			switch action.Code {
-			case pb.BlackjackActionCode_BLACKJACK_ACTION_DOUBLE:
-				if !s.IsAllowAction() {
-					continue
-				}
-				if !s.IsCanDoubleDownBet(action.UserId, wallet.Chips, s.GetCurrentHandN0(action.UserId)) {
-					p.notifyNotEnoughChip(ctx, nk, logger, dispatcher, s, message.GetUserId())
-					continue
-				}
-				chip := s.DoubleDownBet(action.UserId, s.GetCurrentHandN0(action.UserId))
-				p.notifyUpdateBet(ctx, nk, logger, db, dispatcher, s, action.UserId, chip, s.GetCurrentHandN0(action.UserId))
-				cards := p.engine.Deal(1)
-				s.AddCards(cards, action.UserId, s.GetCurrentHandN0(action.UserId))
-				p.broadcastMessage(
-					logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_DEAL),
-					&pb.BlackjackUpdateDeal{
-						IsBanker:                 false,
-						IsRevealBankerHiddenCard: false,
-						UserId:                   action.UserId,
-						HandN0:                   s.GetCurrentHandN0(action.UserId),
-						NewCards:                 cards,
-						Hand:                     s.GetPlayerHand(action.UserId),
-					}, nil, nil, true,
-				)
-				if s.GetCurrentHandN0(action.UserId) == pb.BlackjackHandN0_BLACKJACK_HAND_1ST && len(s.GetPlayerPartOfHand(action.UserId, pb.BlackjackHandN0_BLACKJACK_HAND_2ND).Cards) == 2 {
-					s.SetCurrentHandN0(action.UserId, pb.BlackjackHandN0_BLACKJACK_HAND_2ND)
-					p.turnBaseEngine.RePhase()
-				} else {
-					p.turnBaseEngine.NextPhase()
-				}
-			case pb.BlackjackActionCode_BLACKJACK_ACTION_HIT:
-				if s.IsAllowAction() && s.IsCanHit(action.UserId, s.GetCurrentHandN0(action.UserId)) {
-					cards := p.engine.Deal(1)
-					s.AddCards(cards, action.UserId, s.GetCurrentHandN0(action.UserId))
-					p.broadcastMessage(
-						logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_DEAL),
-						&pb.BlackjackUpdateDeal{
-							IsBanker:                 false,
-							IsRevealBankerHiddenCard: false,
-							UserId:                   action.UserId,
-							HandN0:                   s.GetCurrentHandN0(action.UserId),
-							NewCards:                 cards,
-							Hand:                     s.GetPlayerHand(action.UserId),
-						}, nil, nil, true,
-					)
-					// after that hit, player can't hit anymore -> next hand if possible else next turn
-					if !s.IsCanHit(action.UserId, s.GetCurrentHandN0(action.UserId)) {
-						if s.GetCurrentHandN0(action.UserId) == pb.BlackjackHandN0_BLACKJACK_HAND_1ST && len(s.GetPlayerPartOfHand(action.UserId, pb.BlackjackHandN0_BLACKJACK_HAND_2ND).Cards) == 2 {
-							s.SetCurrentHandN0(action.UserId, pb.BlackjackHandN0_BLACKJACK_HAND_2ND)
-							p.turnBaseEngine.RePhase()
-						} else {
-							p.turnBaseEngine.NextPhase()
-						}
-					} else {
-						p.turnBaseEngine.RePhase()
-					}
-				}
-			case pb.BlackjackActionCode_BLACKJACK_ACTION_INSURANCE:
-				if !s.IsAllowInsurance() {
-					logger.WithField("user_id", message.GetUserId()).Info("not allow insurance")
-					continue
-				}
-				if s.IsCanInsuranceBet(action.UserId, wallet.Chips) {
-					chip := s.InsuranceBet(action.UserId)
-					p.notifyUpdateBet(ctx, nk, logger, db, dispatcher, s, action.UserId, chip, pb.BlackjackHandN0_BLACKJACK_HAND_UNSPECIFIED) // unspecified mean its not in any of 2 hands slot -> insurance slot
-				} else {
-					p.notifyNotEnoughChip(ctx, nk, logger, dispatcher, s, message.GetUserId())
-				}
-			case pb.BlackjackActionCode_BLACKJACK_ACTION_STAY:
-				if s.IsAllowAction() && s.GetCurrentHandN0(action.UserId) == pb.BlackjackHandN0_BLACKJACK_HAND_1ST && len(s.GetPlayerPartOfHand(action.UserId, pb.BlackjackHandN0_BLACKJACK_HAND_2ND).Cards) == 2 {
-					s.SetCurrentHandN0(action.UserId, pb.BlackjackHandN0_BLACKJACK_HAND_2ND)
-					p.turnBaseEngine.RePhase()
-					logger.Info("SWITCH TO 2ND HAND, ACTION_STAY")
-				} else {
-					p.turnBaseEngine.NextPhase()
-					logger.Info("SWITCH TO NEXT PHASE, ACTION_STAY")
-				}
-			case pb.BlackjackActionCode_BLACKJACK_ACTION_SPLIT:
-				if !s.IsAllowAction() {
-					continue
-				}
-				allow, enoughChip := s.IsCanSplitHand(action.UserId, wallet.Chips)
-				if !enoughChip {
-					p.notifyNotEnoughChip(ctx, nk, logger, dispatcher, s, message.GetUserId())
-					continue
-				}
-				if !allow {
-					continue
-				}
-				chip := s.SplitHand(action.UserId)
-				p.notifyUpdateBet(ctx, nk, logger, db, dispatcher, s, action.UserId, chip, s.GetCurrentHandN0(action.UserId))
-				p.broadcastMessage(
-					logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_TABLE),
-					&pb.BlackjackUpdateDesk{
-						IsSplitHand: true,
-						Hand:        s.GetPlayerHand(action.UserId),
-					}, nil, nil, true,
-				)
-				cards := p.engine.Deal(2)
-				s.AddCards([]*pb.Card{cards[0]}, action.UserId, pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
-				p.notifyDealCard(ctx, nk, logger, dispatcher, s, action.UserId, pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
-				s.AddCards([]*pb.Card{cards[1]}, action.UserId, pb.BlackjackHandN0_BLACKJACK_HAND_2ND)
-				p.notifyDealCard(ctx, nk, logger, dispatcher, s, action.UserId, pb.BlackjackHandN0_BLACKJACK_HAND_2ND)
-				p.turnBaseEngine.RePhase()
+			if p.applyAction(ctx, logger, nk, db, dispatcher, s, action, wallet.Chips) {
+				// the scheduled decision of a bot or auto-play is done once its action is applied
+				s.AcceptBotDecision(action.UserId)
 			}
 		case pb.OpCodeRequest_OPCODE_REQUEST_INFO_TABLE:
 			p.broadcastMessage(
@@ -433,7 +407,7 @@ func (p *Processor) ProcessMessageFromUser(
 					IsNewTurn:            false,
 					IsInsuranceTurnEnter: s.IsAllowInsurance(),
 					InTurn:               s.GetCurrentTurn(),
-				}, []runtime.Presence{s.GetPresence(message.GetUserId())}, nil, true,
+				}, []runtime.Presence{s.GetPresenceOrSpectator(message.GetUserId())}, nil, true,
 			)
 		case pb.OpCodeRequest_OPCODE_REQUEST_SYNC_TABLE:
 			msgs := p.engine.RejoinUserMessage(s, message.GetUserId())
@@ -441,11 +415,13 @@ func (p *Processor) ProcessMessageFromUser(
 				continue
 			}
 			for k, msg := range msgs {
-				p.broadcastMessage(logger, dispatcher, int64(k), msg, []runtime.Presence{s.GetPresence(message.GetUserId())}, nil, true)
+				p.broadcastMessage(logger, dispatcher, int64(k), msg, []runtime.Presence{s.GetPresenceOrSpectator(message.GetUserId())}, nil, true)
 			}
+			p.notifySitOut(logger, dispatcher, s, []runtime.Presence{s.GetPresenceOrSpectator(message.GetUserId())})
+			p.sendTableSnapshot(ctx, logger, nk, dispatcher, s, s.GetPresenceOrSpectator(message.GetUserId()))
 		case pb.OpCodeRequest_OPCODE_REQUEST_LEAVE_GAME:
 			userId := message.GetUserId()
-			presence := s.GetPresence(userId)
+			presence := s.GetPresenceOrSpectator(userId)
 			if presence == nil {
 				logger.WithField("user-id", userId).Error("presence not found for leave request")
 				continue
@@ -479,6 +455,8 @@ func (p *Processor) ProcessMessageFromUser(
 				logger.WithField("user-id", userId).Info("user requested to leave table, kicking")
 
 				// Broadcast kick notification before kicking (like ProcessMatchKick does)
+				s.SetKickReason(userId, entity.KickReasonLeaveRequest)
+				p.notifyKickReason(logger, dispatcher, entity.KickReasonLeaveRequest, presence)
 				p.broadcastMessage(
 					logger, dispatcher,
 					int64(pb.OpCodeUpdate_OPCODE_KICK_OFF_THE_TABLE),
@@ -488,45 +466,159 @@ func (p *Processor) ProcessMessageFromUser(
 				)
 				dispatcher.MatchKick([]runtime.Presence{presence})
 			}
+		case entity.OpCodeRequestChat, entity.OpCodeRequestEmote,
+			entity.OpCodeRequestMute, entity.OpCodeRequestReport:
+			p.processChatMessage(ctx, logger, db, dispatcher, s, message)
+		case entity.OpCodeRequestPropBet:
+			p.processPropBet(ctx, logger, nk, dispatcher, s, message)
+		case entity.OpCodeRequestSeatQueue:
+			p.processSeatQueue(ctx, logger, nk, db, dispatcher, s, message)
+		case entity.OpCodeRequestSitSeat:
+			p.processSitSeat(ctx, logger, nk, db, dispatcher, s, message)
+		case entity.OpCodeRequestSitOut:
+			p.processSitOut(logger, dispatcher, s, message)
 		}
 	}
 }
 
-func (p *Processor) ProcessMatchKick(ctx context.Context,
+// applyAction plays the action of the player in turn, chips are the ones in its wallet.
+// It returns whether the action was applied.
+func (p *Processor) applyAction(ctx context.Context,
 	logger runtime.Logger,
 	nk runtime.NakamaModule,
 	db *sql.DB,
 	dispatcher runtime.MatchDispatcher,
 	s *entity.MatchState,
-) {
-	// kick user not interact
-	presenseNotInteract := make(map[string]runtime.Presence)
-	{
-		precenses := s.GetPresences()
-		for _, precense := range precenses {
-			countNoInteract := s.PresencesNoInteract[precense.GetUserId()]
-			if countNoInteract >= 1 {
-				presenseNotInteract[precense.GetUserId()] = precense
-			}
+	action *pb.BlackjackAction,
+	chips int64,
+) bool {
+	switch action.Code {
+	case pb.BlackjackActionCode_BLACKJACK_ACTION_DOUBLE:
+		if !s.IsAllowAction() {
+			return false
+		}
+		if !s.IsCanDoubleDownBet(action.UserId, chips, s.GetCurrentHandN0(action.UserId)) {
+			p.notifyNotEnoughChip(ctx, nk, logger, dispatcher, s, action.UserId)
+			return false
 		}
-		if len(presenseNotInteract) > 0 {
-			list := make([]runtime.Presence, 0)
-			for _, v := range presenseNotInteract {
-				list = append(list, v)
+		chip := s.DoubleDownBet(action.UserId, s.GetCurrentHandN0(action.UserId))
+		p.notifyUpdateBet(ctx, nk, logger, db, dispatcher, s, action.UserId, chip, s.GetCurrentHandN0(action.UserId))
+		cards := p.engine.Deal(1)
+		s.AddCards(cards, action.UserId, s.GetCurrentHandN0(action.UserId))
+		p.broadcastMessage(
+			logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_DEAL),
+			&pb.BlackjackUpdateDeal{
+				IsBanker:                 false,
+				IsRevealBankerHiddenCard: false,
+				UserId:                   action.UserId,
+				HandN0:                   s.GetCurrentHandN0(action.UserId),
+				NewCards:                 cards,
+				Hand:                     s.GetPlayerHand(action.UserId),
+			}, nil, nil, true,
+		)
+		if s.GetCurrentHandN0(action.UserId) == pb.BlackjackHandN0_BLACKJACK_HAND_1ST && len(s.GetPlayerPartOfHand(action.UserId, pb.BlackjackHandN0_BLACKJACK_HAND_2ND).Cards) == 2 {
+			s.SetCurrentHandN0(action.UserId, pb.BlackjackHandN0_BLACKJACK_HAND_2ND)
+			p.continueTurn(logger, dispatcher, s, action.UserId)
+		} else {
+			p.endTurn(logger, dispatcher, s, action.UserId)
+		}
+		return true
+	case pb.BlackjackActionCode_BLACKJACK_ACTION_HIT:
+		if s.IsAllowAction() && s.IsCanHit(action.UserId, s.GetCurrentHandN0(action.UserId)) {
+			cards := p.engine.Deal(1)
+			s.AddCards(cards, action.UserId, s.GetCurrentHandN0(action.UserId))
+			p.broadcastMessage(
+				logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_DEAL),
+				&pb.BlackjackUpdateDeal{
+					IsBanker:                 false,
+					IsRevealBankerHiddenCard: false,
+					UserId:                   action.UserId,
+					HandN0:                   s.GetCurrentHandN0(action.UserId),
+					NewCards:                 cards,
+					Hand:                     s.GetPlayerHand(action.UserId),
+				}, nil, nil, true,
+			)
+			// after that hit, player can't hit anymore -> next hand if possible else next turn
+			if !s.IsCanHit(action.UserId, s.GetCurrentHandN0(action.UserId)) {
+				if s.GetCurrentHandN0(action.UserId) == pb.BlackjackHandN0_BLACKJACK_HAND_1ST && len(s.GetPlayerPartOfHand(action.UserId, pb.BlackjackHandN0_BLACKJACK_HAND_2ND).Cards) == 2 {
+					s.SetCurrentHandN0(action.UserId, pb.BlackjackHandN0_BLACKJACK_HAND_2ND)
+					p.continueTurn(logger, dispatcher, s, action.UserId)
+				} else {
+					p.endTurn(logger, dispatcher, s, action.UserId)
+				}
+			} else {
+				p.continueTurn(logger, dispatcher, s, action.UserId)
 			}
-			p.broadcastMessage(logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_KICK_OFF_THE_TABLE), nil, list, nil, true)
-			dispatcher.MatchKick(list)
+			return true
+		}
+	case pb.BlackjackActionCode_BLACKJACK_ACTION_INSURANCE:
+		if !s.IsAllowInsurance() {
+			logger.WithField("user_id", action.UserId).Info("not allow insurance")
+			return false
+		}
+		if !s.IsCanInsuranceBet(action.UserId, chips) {
+			p.notifyNotEnoughChip(ctx, nk, logger, dispatcher, s, action.UserId)
+			return false
+		}
+		chip := s.InsuranceBet(action.UserId)
+		p.notifyUpdateBet(ctx, nk, logger, db, dispatcher, s, action.UserId, chip, pb.BlackjackHandN0_BLACKJACK_HAND_UNSPECIFIED) // unspecified mean its not in any of 2 hands slot -> insurance slot
+		return true
+	case pb.BlackjackActionCode_BLACKJACK_ACTION_STAY:
+		if s.IsAllowAction() && s.GetCurrentHandN0(action.UserId) == pb.BlackjackHandN0_BLACKJACK_HAND_1ST && len(s.GetPlayerPartOfHand(action.UserId, pb.BlackjackHandN0_BLACKJACK_HAND_2ND).Cards) == 2 {
+			s.SetCurrentHandN0(action.UserId, pb.BlackjackHandN0_BLACKJACK_HAND_2ND)
+			p.continueTurn(logger, dispatcher, s, action.UserId)
+			logger.Info("SWITCH TO 2ND HAND, ACTION_STAY")
+		} else {
+			p.endTurn(logger, dispatcher, s, action.UserId)
+			logger.Info("SWITCH TO NEXT PHASE, ACTION_STAY")
+		}
+		return true
+	case pb.BlackjackActionCode_BLACKJACK_ACTION_SPLIT:
+		if !s.IsAllowAction() {
+			return false
+		}
+		allow, enoughChip := s.IsCanSplitHand(action.UserId, chips)
+		if !enoughChip {
+			p.notifyNotEnoughChip(ctx, nk, logger, dispatcher, s, action.UserId)
+			return false
+		}
+		if !allow {
+			return false
 		}
+		chip := s.SplitHand(action.UserId)
+		p.notifyUpdateBet(ctx, nk, logger, db, dispatcher, s, action.UserId, chip, s.GetCurrentHandN0(action.UserId))
+		p.broadcastMessage(
+			logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_TABLE),
+			&pb.BlackjackUpdateDesk{
+				IsSplitHand: true,
+				Hand:        s.GetPlayerHand(action.UserId),
+			}, nil, nil, true,
+		)
+		cards := p.engine.Deal(2)
+		s.AddCards([]*pb.Card{cards[0]}, action.UserId, pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
+		p.notifyDealCard(ctx, nk, logger, dispatcher, s, action.UserId, pb.BlackjackHandN0_BLACKJACK_HAND_1ST)
+		s.AddCards([]*pb.Card{cards[1]}, action.UserId, pb.BlackjackHandN0_BLACKJACK_HAND_2ND)
+		p.notifyDealCard(ctx, nk, logger, dispatcher, s, action.UserId, pb.BlackjackHandN0_BLACKJACK_HAND_2ND)
+		p.continueTurn(logger, dispatcher, s, action.UserId)
+		return true
 	}
+	return false
+}
+
+func (p *Processor) ProcessMatchKick(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	db *sql.DB,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+) {
+	// idle users are handled by the AFK policy when the next round prepares
 	// kick by not enough chip
 	{
-		list := make([]runtime.Presence, 0)
-		for _, precense := range s.GetPresences() {
-			if _, exist := presenseNotInteract[precense.GetUserId()]; !exist {
-				list = append(list, precense)
-			}
-		}
+		list := s.GetPresences()
 		minChipRequire := s.Label.Bet.AgLeave
+		p.notifyKickReason(logger, dispatcher, entity.KickReasonNotEnoughChips,
+			p.presencesBelowChips(ctx, logger, nk, minChipRequire, list)...)
 		lib.MatchKick(ctx, logger, nk, dispatcher, minChipRequire, list...)
 	}
 }
@@ -556,8 +648,10 @@ func (p *Processor) notifyUpdateTurn(
 	for _, presence := range s.GetPresences() {
 		if presence.GetUserId() == s.GetCurrentTurn() {
 			msg.Actions = legalActions
+			msg.TimeBank = int64(s.GetTimeBank(presence.GetUserId()))
 		} else {
 			msg.Actions = nil
+			msg.TimeBank = 0
 		}
 		p.broadcastMessage(
 			logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_TABLE),
@@ -566,6 +660,173 @@ func (p *Processor) notifyUpdateTurn(
 	}
 }
 
+// extendTurnFromTimeBank pays for more time from the bank of the acting player when its turn runs out
+func (p *Processor) extendTurnFromTimeBank(logger runtime.Logger,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+) {
+	if p.turnBaseEngine == nil || !p.turnBaseEngine.IsTimeout() || p.turnBaseEngine.GetCurrentRound() != "playing" {
+		return
+	}
+	userId := p.turnBaseEngine.GetCurrentPlayer()
+	if userId != s.GetCurrentTurn() || !s.IsAllowAction() {
+		return
+	}
+	if extension := p.drawTimeBank(logger, dispatcher, s, userId); extension > 0 {
+		p.turnBaseEngine.ExtendCountDown(extension)
+		s.SetUpCountDown(extension)
+	}
+}
+
+// drawTimeBank takes an extension out of the time bank of a player whose turn ran out, 0 when none is due
+func (p *Processor) drawTimeBank(logger runtime.Logger,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	userId string,
+) time.Duration {
+	// the bank is not drawn while the hand is played for the player
+	if s.IsBot(userId) || s.IsDisconnected(userId) || s.IsPlayedOnTimeout(userId) {
+		return 0
+	}
+	extension := s.DrawTimeBank(userId)
+	if extension <= 0 {
+		return 0
+	}
+	logger.WithField("user_id", userId).WithField("extension", extension).Info("turn extended from time bank")
+	if presence := s.GetPresence(userId); presence != nil {
+		p.notifyTimeBank(logger, dispatcher, s, presence, extension)
+	}
+	return time.Duration(extension) * time.Second
+}
+
+// applyTimeoutAction plays for the players who let their turn or the insurance round run out,
+// following the timeout policy of the table, and tells the table what was played
+func (p *Processor) applyTimeoutAction(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	db *sql.DB,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+) {
+	if p.turnBaseEngine == nil || !p.turnBaseEngine.IsTimeout() {
+		return
+	}
+	switch p.turnBaseEngine.GetCurrentRound() {
+	case "insurance":
+		if !s.IsAllowInsurance() {
+			return
+		}
+		userIds := make([]string, 0)
+		for _, presence := range s.GetPlayingPresences() {
+			if userId := presence.GetUserId(); !s.IsBot(userId) && s.IsBet(userId) && !s.HasInsuranceBet(userId) {
+				userIds = append(userIds, userId)
+			}
+		}
+		if len(userIds) == 0 {
+			return
+		}
+		wallets, err := entity.ReadWalletUsers(ctx, nk, logger, userIds...)
+		if err != nil {
+			logger.WithField("users", strings.Join(userIds, ", ")).WithField("err", err).Error("read wallets for insurance timeout failed")
+			return
+		}
+		// only the players who could still take the insurance let it run out
+		for _, wallet := range wallets {
+			if !s.IsInsurancePending(wallet.UserId, wallet.Chips) {
+				continue
+			}
+			update := s.InsuranceTimeoutAction(wallet.UserId)
+			if !update.Decline {
+				p.applyAction(ctx, logger, nk, db, dispatcher, s,
+					&pb.BlackjackAction{UserId: wallet.UserId, Code: update.Code}, wallet.Chips)
+				update.Decline = !s.HasInsuranceBet(wallet.UserId)
+			}
+			p.broadcastJson(logger, dispatcher, entity.OpCodeUpdateAction, update, nil, true)
+		}
+	case "playing":
+		userId := p.turnBaseEngine.GetCurrentPlayer()
+		if userId != s.GetCurrentTurn() || !s.IsAllowAction() || len(s.GetLegalActions()) == 0 {
+			return
+		}
+		code := p.playTimedOut(ctx, logger, nk, db, dispatcher, s, userId)
+		// the rest of the hand is played at auto play pace instead of a full turn per card
+		if code == pb.BlackjackActionCode_BLACKJACK_ACTION_HIT && p.turnBaseEngine.GetCurrentPlayer() == userId {
+			p.turnBaseEngine.ExtendCountDown(entity.AutoPlayDelay)
+		}
+	}
+}
+
+// playTimedOut plays the timeout action of the table for userId and returns the action played
+func (p *Processor) playTimedOut(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	db *sql.DB,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	userId string,
+) pb.BlackjackActionCode {
+	update := s.TimeoutAction(userId)
+	s.MarkTimedOut(userId)
+	logger.WithField("user_id", userId).WithField("action", update.Code).WithField("forfeit", update.Forfeit).Info("turn timed out")
+	if update.Forfeit {
+		s.ForfeitHand(userId, update.HandN0)
+	}
+	p.broadcastJson(logger, dispatcher, entity.OpCodeUpdateAction, update, nil, true)
+	p.applyAction(ctx, logger, nk, db, dispatcher, s,
+		&pb.BlackjackAction{UserId: userId, Code: update.Code}, 0)
+	return update.Code
+}
+
+// endTurn moves on once userId played all its hands, to the next player or,
+// when all players decide at once, by closing its decision
+func (p *Processor) endTurn(logger runtime.Logger,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	userId string,
+) {
+	if !s.IsSimultaneousRound() {
+		p.turnBaseEngine.NextPhase()
+		return
+	}
+	s.FinishDecision(userId)
+	p.broadcastJson(logger, dispatcher, entity.OpCodeUpdateDecisions, s.GetDecisions(), nil, true)
+}
+
+// continueTurn gives userId a new turn on the hand it plays now
+func (p *Processor) continueTurn(logger runtime.Logger,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	userId string,
+) {
+	if !s.IsSimultaneousRound() {
+		p.turnBaseEngine.RePhase()
+		return
+	}
+	s.RenewDecision(userId, time.Now(), s.TableConfig().TurnDuration())
+	if presence := s.GetPresence(userId); presence != nil {
+		p.notifyDecisionTurn(logger, dispatcher, s, presence)
+	}
+}
+
+// notifyTimeBank sends the acting player the seconds its turn was extended by and what is left in its time bank
+func (p *Processor) notifyTimeBank(logger runtime.Logger,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	presence runtime.Presence,
+	extended int,
+) {
+	userId := presence.GetUserId()
+	p.broadcastMessage(
+		logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_TABLE),
+		&pb.BlackjackUpdateDesk{
+			InTurn:           userId,
+			Hand_N0:          s.GetCurrentHandN0(userId),
+			TimeBank:         int64(s.GetTimeBank(userId)),
+			TimeBankExtended: int64(extended),
+		}, []runtime.Presence{presence}, nil, true,
+	)
+}
+
 func (p *Processor) notifyUpdateBet(
 	ctx context.Context,
 	nk runtime.NakamaModule,
@@ -615,7 +876,9 @@ func (p *Processor) notifyUpdateBet(
 	if updateDesk.Error != nil {
 		return
 	}
-	p.updateChipByResultGameFinish(ctx, nk, logger, db, &pb.BalanceResult{Updates: []*pb.BalanceUpdate{balance}})
+	if err := p.updateChipByResultGameFinish(ctx, nk, logger, db, &pb.BalanceResult{Updates: []*pb.BalanceUpdate{balance}}, ""); err == nil {
+		s.RecordStake(userId, chip)
+	}
 }
 
 func (p *Processor) notifyNotEnoughChip(
@@ -654,7 +917,8 @@ func (p *Processor) updateChipByResultGameFinish(
 	logger runtime.Logger,
 	db *sql.DB,
 	balanceResult *pb.BalanceResult,
-) {
+	action entity.WalletAction,
+) error {
 	walletUpdates := make([]*runtime.WalletUpdate, 0, len(balanceResult.Updates))
 	for _, update := range balanceResult.Updates {
 		amountChip := update.AmountChipAdd
@@ -662,13 +926,16 @@ func (p *Processor) updateChipByResultGameFinish(
 			"chips": amountChip,
 		}
 		metadata := map[string]any{"game_reward": entity.ModuleName}
+		if action != "" {
+			metadata["action"] = string(action)
+		}
 		walletUpdates = append(walletUpdates, &runtime.WalletUpdate{
 			UserID:    update.UserId,
 			Changeset: changeset,
 			Metadata:  metadata,
 		})
-		// Add VIP farm accumulation for winning users
-		if amountChip > 0 {
+		// Add VIP farm accumulation for winning users, side bets and refunds do not farm
+		if amountChip > 0 && action == "" {
 			if err := lib.AddUserVipFarmAccumulation(ctx, entity.DefaultMarshaler, entity.DefaulUnmarshaler, logger, db, nk, update.UserId, pb.VipFarmCumulativeType_VIP_FARM_CUMULATIVE_TYPE_BETTING, amountChip); err != nil {
 				logger.
 					WithField("user-id", update.UserId).
@@ -683,7 +950,9 @@ func (p *Processor) updateChipByResultGameFinish(
 		logger.WithField("payload", string(payload)).
 			WithField("err", err).
 			Error("wallet-update-error")
+		return err
 	}
+	return nil
 }
 
 func (p *Processor) calcRewardForUserPlaying(
@@ -732,7 +1001,12 @@ func (p *Processor) calcRewardForUserPlaying(
 		balance.AmoutChipBet = betResult.First.BetAmount + betResult.Second.BetAmount + betResult.Insurance.BetAmount
 		chipWin := betResult.First.Total + betResult.Second.Total + betResult.Insurance.Total
 		balance.TotalChipInMatch = -balance.AmoutChipBet
-		if chipWin > 0 {
+		// bots play with house money, charging them a fee is not revenue
+		if chipWin > 0 && s.IsBot(betResult.UserId) {
+			balance.AmountChipAdd = chipWin
+			balance.TotalChipInMatch += balance.AmountChipAdd
+			balance.AmountChipCurrent = balance.AmountChipBefore + balance.AmountChipAdd
+		} else if chipWin > 0 {
 			fee := int64(0)
 			presence, ok := s.GetPresence(betResult.UserId).(entity.MyPrecense)
 			percentFeeGame := entity.GetFeeGameByLevel(0)
@@ -772,40 +1046,48 @@ func (p *Processor) calcRewardForUserPlaying(
 	return &balanceResult, totalFee
 }
 
+// notifyInitialDealCard schedules the initial deal on the timeline, a step per seat
+// then the dealer, and returns the offset of the last step
 func (p *Processor) notifyInitialDealCard(
 	ctx context.Context,
 	nk runtime.NakamaModule,
 	logger runtime.Logger,
 	dispatcher runtime.MatchDispatcher,
 	s *entity.MatchState,
-) error {
+) time.Duration {
+	timeline := s.Timeline()
+	offset := time.Duration(0)
 	for _, presence := range s.GetPlayingPresences() {
-		p.broadcastMessage(
-			logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_DEAL),
-			&pb.BlackjackUpdateDeal{
-				IsBanker:                 false,
-				IsRevealBankerHiddenCard: false,
-				UserId:                   presence.GetUserId(),
-				NewCards:                 s.GetPlayerHand(presence.GetUserId()).First.Cards,
-				Hand:                     s.GetPlayerHand(presence.GetUserId()),
-				HandN0:                   pb.BlackjackHandN0_BLACKJACK_HAND_1ST,
-			}, nil, nil, true,
-		)
-		// initial legal actions for all user
-		p.broadcastMessage(
-			logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_TABLE),
-			&pb.BlackjackUpdateDesk{
-				IsInsuranceTurnEnter: false,
-				IsNewTurn:            false,
-				Hand_N0:              pb.BlackjackHandN0_BLACKJACK_HAND_1ST,
-				IsUpdateBet:          false,
-				Actions: &pb.BlackjackLegalActions{
-					UserId:  presence.GetUserId(),
-					Actions: s.GetLegalActionsByUserId(presence.GetUserId()),
-				},
-				IsSplitHand: false,
-			}, []runtime.Presence{presence}, nil, true,
-		)
+		presence := presence
+		timeline.Schedule(entity.TimelineStepDeal, presence.GetUserId(), offset, func() {
+			p.broadcastMessage(
+				logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_DEAL),
+				&pb.BlackjackUpdateDeal{
+					IsBanker:                 false,
+					IsRevealBankerHiddenCard: false,
+					UserId:                   presence.GetUserId(),
+					NewCards:                 s.GetPlayerHand(presence.GetUserId()).First.Cards,
+					Hand:                     s.GetPlayerHand(presence.GetUserId()),
+					HandN0:                   pb.BlackjackHandN0_BLACKJACK_HAND_1ST,
+				}, nil, nil, true,
+			)
+			// initial legal actions for all user
+			p.broadcastMessage(
+				logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_TABLE),
+				&pb.BlackjackUpdateDesk{
+					IsInsuranceTurnEnter: false,
+					IsNewTurn:            false,
+					Hand_N0:              pb.BlackjackHandN0_BLACKJACK_HAND_1ST,
+					IsUpdateBet:          false,
+					Actions: &pb.BlackjackLegalActions{
+						UserId:  presence.GetUserId(),
+						Actions: s.GetLegalActionsByUserId(presence.GetUserId()),
+					},
+					IsSplitHand: false,
+				}, []runtime.Presence{presence}, nil, true,
+			)
+		})
+		offset += entity.DealPace
 	}
 	dealerCards := []*pb.Card{
 		s.GetDealerHand().First.GetCards()[0],
@@ -814,42 +1096,80 @@ func (p *Processor) notifyInitialDealCard(
 			Suit: pb.CardSuit_SUIT_UNSPECIFIED,
 		},
 	}
-	p.broadcastMessage(
-		logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_DEAL),
-		&pb.BlackjackUpdateDeal{
-			IsBanker:                 true,
-			IsRevealBankerHiddenCard: false,
-			UserId:                   "",
-			NewCards:                 dealerCards,
-			HandN0:                   pb.BlackjackHandN0_BLACKJACK_HAND_1ST,
-			Hand: &pb.BlackjackPlayerHand{
-				First: &pb.BlackjackHand{
-					Cards: dealerCards,
+	timeline.Schedule(entity.TimelineStepDealerDeal, "", offset, func() {
+		p.broadcastMessage(
+			logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_DEAL),
+			&pb.BlackjackUpdateDeal{
+				IsBanker:                 true,
+				IsRevealBankerHiddenCard: false,
+				UserId:                   "",
+				NewCards:                 dealerCards,
+				HandN0:                   pb.BlackjackHandN0_BLACKJACK_HAND_1ST,
+				Hand: &pb.BlackjackPlayerHand{
+					First: &pb.BlackjackHand{
+						Cards: dealerCards,
+					},
 				},
-			},
-		}, nil, nil, true,
-	)
-	return nil
+			}, nil, nil, true,
+		)
+	})
+	return offset
 }
 
-func (p *Processor) revealDealerHiddenCard(
-	ctx context.Context,
-	nk runtime.NakamaModule,
-	logger runtime.Logger,
+// scheduleDealerSequence starts the timeline of the dealer play, the reveal then each draw
+// then the total, and returns the offset of the total
+func (p *Processor) scheduleDealerSequence(logger runtime.Logger,
 	dispatcher runtime.MatchDispatcher,
 	s *entity.MatchState,
-) {
-	p.broadcastMessage(
-		logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_DEAL),
-		&pb.BlackjackUpdateDeal{
-			IsBanker:                 true,
-			IsRevealBankerHiddenCard: true,
-			UserId:                   "",
-			NewCards:                 []*pb.Card{s.GetDealerHand().First.Cards[1]},
-			HandN0:                   pb.BlackjackHandN0_BLACKJACK_HAND_1ST,
-			Hand:                     s.GetDealerHand(),
-		}, nil, nil, true,
-	)
+	reveal *pb.BlackjackUpdateDeal,
+	draws []*pb.BlackjackUpdateDeal,
+) time.Duration {
+	timeline := s.Timeline()
+	timeline.Start(time.Now())
+	deals := append([]*pb.BlackjackUpdateDeal{reveal}, draws...)
+	offset := time.Duration(0)
+	for _, step := range s.BuildDealerSequence().Steps {
+		step := step
+		var deal *pb.BlackjackUpdateDeal
+		timelineStep := entity.TimelineStepDealerDraw
+		switch step.Step {
+		case entity.DealerStepReveal:
+			deal = deals[0]
+			timelineStep = entity.TimelineStepReveal
+		case entity.DealerStepDraw:
+			deal = deals[step.Seq-1]
+		case entity.DealerStepTotal:
+			timelineStep = entity.TimelineStepDealerTotal
+		}
+		timeline.Schedule(timelineStep, "", offset, func() {
+			s.ShowDealerStep(step.Seq)
+			if deal != nil {
+				p.broadcastMessage(
+					logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_DEAL),
+					deal, nil, nil, true,
+				)
+			}
+			p.broadcastJson(logger, dispatcher, entity.OpCodeUpdateDealerStep, step, nil, true)
+		})
+		switch step.Step {
+		case entity.DealerStepReveal:
+			offset += entity.RevealPace
+		case entity.DealerStepDraw:
+			offset += entity.DealerDrawPace
+		}
+	}
+	return offset
+}
+
+func (p *Processor) revealDealerHiddenCardMessage(s *entity.MatchState) *pb.BlackjackUpdateDeal {
+	return &pb.BlackjackUpdateDeal{
+		IsBanker:                 true,
+		IsRevealBankerHiddenCard: true,
+		UserId:                   "",
+		NewCards:                 []*pb.Card{s.GetDealerHand().First.Cards[1]},
+		HandN0:                   pb.BlackjackHandN0_BLACKJACK_HAND_1ST,
+		Hand:                     s.GetDealerHand(),
+	}
 }
 
 func (p *Processor) notifyDealCard(
@@ -861,6 +1181,15 @@ func (p *Processor) notifyDealCard(
 	userId string,
 	handN0 pb.BlackjackHandN0,
 ) error {
+	return p.broadcastMessage(
+		logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_DEAL),
+		p.dealCardMessage(s, userId, handN0),
+		nil, nil, true,
+	)
+}
+
+// dealCardMessage builds the deal of the last card of a hand, the dealer's when userId is empty
+func (p *Processor) dealCardMessage(s *entity.MatchState, userId string, handN0 pb.BlackjackHandN0) *pb.BlackjackUpdateDeal {
 	isBanker := false
 	var hands *pb.BlackjackPlayerHand
 	if userId == "" {
@@ -876,7 +1205,7 @@ func (p *Processor) notifyDealCard(
 		hand = hands.Second
 	}
 
-	msg := &pb.BlackjackUpdateDeal{
+	return &pb.BlackjackUpdateDeal{
 		UserId:                   userId,
 		IsBanker:                 isBanker,
 		IsRevealBankerHiddenCard: false,
@@ -886,9 +1215,4 @@ func (p *Processor) notifyDealCard(
 		},
 		Hand: hands,
 	}
-	return p.broadcastMessage(
-		logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_DEAL),
-		msg,
-		nil, nil, true,
-	)
 }
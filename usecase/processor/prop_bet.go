@@ -0,0 +1,150 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/nk-nigeria/blackjack-module/entity"
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+// processPropBet places a prop bet of a presence not playing the round
+func (p *Processor) processPropBet(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	message runtime.MatchData,
+) {
+	userId := message.GetUserId()
+	req := &entity.PropBetRequest{}
+	if err := json.Unmarshal(message.GetData(), req); err != nil {
+		logger.WithField("user-id", userId).WithField("error", err).Error("error-parse-prop-bet-request")
+		return
+	}
+	wallet, err := entity.ReadWalletUser(ctx, nk, logger, userId)
+	if err != nil {
+		logger.Error("error.read-user-wallet")
+		return
+	}
+	if code := s.CanPlacePropBet(userId, req, wallet.Chips); code != "" {
+		p.broadcastJson(logger, dispatcher, entity.OpCodeUpdatePropBetError,
+			&entity.PropBetError{Code: code}, []runtime.Presence{message}, true)
+		return
+	}
+	if err := p.updatePropBetWallets(ctx, nk, logger, entity.WalletActionPropBet,
+		map[string]int64{userId: -req.Chips}); err != nil {
+		return
+	}
+	s.AddPropBet(userId, req.Kind, req.Chips)
+	s.ResetUserNotInteract(userId)
+	p.broadcastJson(logger, dispatcher, entity.OpCodeUpdatePropBet,
+		&entity.PropBetUpdate{UserId: userId, Bets: s.GetPropBets(userId)}, nil, true)
+}
+
+// settlePropBets pays the prop bets of the finished round and returns their balance updates and results
+func (p *Processor) settlePropBets(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	s *entity.MatchState,
+) ([]*pb.BalanceUpdate, []*entity.PropBetResult) {
+	defer s.ResetPropBets()
+	bettors := s.GetPropBettors()
+	if len(bettors) == 0 {
+		return nil, nil
+	}
+	results := s.SettlePropBets(s.PropBetOutcome())
+	payouts := make(map[string]int64)
+	for _, r := range results {
+		if r.TotalPayout > 0 {
+			payouts[r.UserId] = r.TotalPayout
+		}
+	}
+	p.updatePropBetWallets(ctx, nk, logger, entity.WalletActionPropBetWin, payouts)
+
+	chips := make(map[string]int64)
+	if wallets, err := entity.ReadWalletUsers(ctx, nk, logger, bettors...); err == nil {
+		for _, w := range wallets {
+			chips[w.UserId] = w.Chips
+		}
+	}
+	updates := make([]*pb.BalanceUpdate, 0, len(results))
+	for _, r := range results {
+		updates = append(updates, &pb.BalanceUpdate{
+			UserId:            r.UserId,
+			AmountChipBefore:  chips[r.UserId] - r.TotalPayout,
+			AmountChipAdd:     r.TotalPayout,
+			AmountChipCurrent: chips[r.UserId],
+			AmoutChipBet:      r.TotalBet,
+			TotalChipInMatch:  r.TotalPayout - r.TotalBet,
+		})
+		logger.WithField("user_id", r.UserId).
+			WithField("bet", r.TotalBet).
+			WithField("payout", r.TotalPayout).
+			Info("prop bets settled")
+	}
+	return updates, results
+}
+
+// notifyPropBetResults sends each bettor still at the table the result of their prop bets
+func (p *Processor) notifyPropBetResults(logger runtime.Logger,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	results []*entity.PropBetResult,
+) {
+	for _, r := range results {
+		if presence := s.GetPresenceOrSpectator(r.UserId); presence != nil {
+			p.broadcastJson(logger, dispatcher, entity.OpCodeUpdatePropBetResult,
+				r, []runtime.Presence{presence}, true)
+		}
+	}
+}
+
+// ProcessPropBetRefund gives the stakes of prop bets back when the round is not played
+func (p *Processor) ProcessPropBetRefund(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+) {
+	defer s.ResetPropBets()
+	refunds := make(map[string]int64)
+	for _, userId := range s.GetPropBettors() {
+		for _, bet := range s.GetPropBets(userId) {
+			refunds[userId] += bet.Chips
+		}
+	}
+	if len(refunds) == 0 {
+		return
+	}
+	logger.WithField("users", len(refunds)).Info("refund prop bets of a cancelled round")
+	p.updatePropBetWallets(ctx, nk, logger, entity.WalletActionPropBetRefund, refunds)
+	for userId := range refunds {
+		if presence := s.GetPresenceOrSpectator(userId); presence != nil {
+			p.broadcastJson(logger, dispatcher, entity.OpCodeUpdatePropBet,
+				&entity.PropBetUpdate{UserId: userId, Bets: []*entity.PropBet{}}, []runtime.Presence{presence}, true)
+		}
+	}
+}
+
+// updatePropBetWallets adds amounts to the wallets of the bettors, tagged with action
+func (p *Processor) updatePropBetWallets(ctx context.Context,
+	nk runtime.NakamaModule,
+	logger runtime.Logger,
+	action entity.WalletAction,
+	amounts map[string]int64,
+) error {
+	if len(amounts) == 0 {
+		return nil
+	}
+	balanceResult := &pb.BalanceResult{}
+	for userId, amount := range amounts {
+		balanceResult.Updates = append(balanceResult.Updates, &pb.BalanceUpdate{
+			UserId:        userId,
+			AmountChipAdd: amount,
+		})
+	}
+	// prop bets do not farm VIP points, the db is not needed
+	return p.updateChipByResultGameFinish(ctx, nk, logger, nil, balanceResult, action)
+}
@@ -0,0 +1,32 @@
+package processor
+
+import (
+	"context"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/nk-nigeria/blackjack-module/entity"
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+// notifyAbsentSettlements sends a persistent round summary to the settled players
+// who were not at the table to see the reward
+func (p *Processor) notifyAbsentSettlements(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	s *entity.MatchState,
+	balanceResult *pb.BalanceResult,
+) {
+	for _, update := range s.AbsentSettledPlayers(balanceResult) {
+		summary := s.RoundSummary(update)
+		if err := nk.NotificationSend(ctx, update.UserId, "Blackjack round settled",
+			summary.Content(), entity.NotificationCodeRoundSummary, "", true); err != nil {
+			logger.WithField("user_id", update.UserId).
+				WithField("err", err).
+				Error("round-summary-notification-error")
+			continue
+		}
+		logger.WithField("user_id", update.UserId).
+			WithField("net", summary.Net).
+			Info("round summary sent to absent player")
+	}
+}
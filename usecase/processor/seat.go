@@ -0,0 +1,84 @@
+package processor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/nk-nigeria/blackjack-module/entity"
+)
+
+// processSitSeat moves a seated user to the requested seat, a spectator takes the
+// seat directly when nobody is waiting in the seat queue
+func (m *BaseProcessor) processSitSeat(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	db *sql.DB,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	message runtime.MatchData,
+) {
+	userId := message.GetUserId()
+	req := &entity.SitSeatRequest{}
+	if err := json.Unmarshal(message.GetData(), req); err != nil {
+		logger.WithField("user-id", userId).WithField("error", err).Error("error-parse-sit-seat-request")
+		return
+	}
+	if s.IsSpectator(userId) {
+		m.sitSpectator(ctx, logger, nk, db, dispatcher, s, message, req.Seat)
+		return
+	}
+	if code := s.SitAtSeat(userId, req.Seat); code != "" {
+		m.notifySeatError(logger, dispatcher, message, code)
+		return
+	}
+	s.ResetUserNotInteract(userId)
+	logger.WithField("user_id", userId).WithField("seat", req.Seat).Info("user changed seat")
+	m.notifyUserChange(ctx, nk, logger, db, dispatcher, s, nil)
+}
+
+func (m *BaseProcessor) sitSpectator(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	db *sql.DB,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	message runtime.MatchData,
+	seat int,
+) {
+	userId := message.GetUserId()
+	if queue := s.GetSeatQueue(); len(queue) > 0 && queue[0] != userId {
+		m.notifySeatError(logger, dispatcher, message, entity.SeatErrorQueue)
+		return
+	}
+	if !s.IsSeatFree(seat) || !s.HasFreeSeat() {
+		m.notifySeatError(logger, dispatcher, message, entity.SeatErrorTaken)
+		return
+	}
+	wallet, err := entity.ReadWalletUser(ctx, nk, logger, userId)
+	if err != nil || wallet.Chips < int64(s.Label.MarkUnit) {
+		logger.WithField("user_id", userId).Info("spectator can't sit, chip balance not enough")
+		m.notifySeatError(logger, dispatcher, message, entity.SeatErrorChips)
+		return
+	}
+	presence := s.GetPresenceOrSpectator(userId)
+	s.RemoveSpectator(userId)
+	s.AddPresence(ctx, nk, db, []runtime.Presence{presence})
+	s.SitAtSeat(userId, seat)
+	logger.WithField("user_id", userId).WithField("seat", seat).Info("spectator sat at a seat")
+	m.broadcastJson(logger, dispatcher, entity.OpCodeUpdateSeatPromoted,
+		&entity.SeatPromoted{UserId: userId}, nil, true)
+	s.UpdateLabel()
+	m.notifySeatQueue(logger, dispatcher, s, nil)
+	m.notifyUserChange(ctx, nk, logger, db, dispatcher, s, nil)
+}
+
+func (m *BaseProcessor) notifySeatError(logger runtime.Logger,
+	dispatcher runtime.MatchDispatcher,
+	message runtime.MatchData,
+	code string,
+) {
+	m.broadcastJson(logger, dispatcher, entity.OpCodeUpdateSeatError,
+		&entity.SeatError{Code: code}, []runtime.Presence{message}, true)
+}
@@ -0,0 +1,90 @@
+package processor
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/nk-nigeria/blackjack-module/entity"
+	pb "github.com/nk-nigeria/cgp-common/proto"
+)
+
+// startDecisions opens the playing round of a simultaneous table, every player gets its
+// legal actions at once and decides against the dealer upcard
+func (p *Processor) startDecisions(logger runtime.Logger,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+) {
+	turn := s.TableConfig().TurnDuration()
+	userIds := make([]string, 0)
+	for _, presence := range s.GetPlayingPresences() {
+		userIds = append(userIds, presence.GetUserId())
+	}
+	s.SetCurrentTurn("")
+	s.StartDecisions(userIds, time.Now(), turn)
+	s.SetUpCountDown(turn)
+	p.turnBaseEngine.ExtendCountDown(turn)
+	for _, presence := range s.GetPlayingPresences() {
+		if s.IsInTurn(presence.GetUserId()) {
+			p.notifyDecisionTurn(logger, dispatcher, s, presence)
+		}
+	}
+	p.broadcastJson(logger, dispatcher, entity.OpCodeUpdateDecisions, s.GetDecisions(), nil, true)
+}
+
+// processDecisions times out the players of a simultaneous round one by one and closes
+// the round once everyone stood or timed out
+func (p *Processor) processDecisions(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	db *sql.DB,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+) {
+	// players kicked by an operator stand without waiting for their decision to expire
+	for _, presence := range s.GetPlayingPresences() {
+		if s.IsBanned(presence.GetUserId()) {
+			p.standHands(ctx, logger, nk, db, dispatcher, s, presence.GetUserId())
+		}
+	}
+	now := time.Now()
+	for _, userId := range s.ExpiredDecisions(now) {
+		if extension := p.drawTimeBank(logger, dispatcher, s, userId); extension > 0 {
+			s.RenewDecision(userId, now, extension)
+			continue
+		}
+		// the rest of the hand is played at auto play pace instead of a full turn per card
+		if p.playTimedOut(ctx, logger, nk, db, dispatcher, s, userId) == pb.BlackjackActionCode_BLACKJACK_ACTION_HIT {
+			s.RenewDecision(userId, now, entity.AutoPlayDelay)
+		}
+	}
+	if s.AllDecided() {
+		s.SetIsGameEnded(true)
+		return
+	}
+	// the round is timed by the decisions, keep the engine from moving on
+	p.turnBaseEngine.ExtendCountDown(s.TableConfig().TurnDuration())
+}
+
+// notifyDecisionTurn sends a player of a simultaneous round the legal actions of the hand it plays now
+func (p *Processor) notifyDecisionTurn(logger runtime.Logger,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	presence runtime.Presence,
+) {
+	userId := presence.GetUserId()
+	p.broadcastMessage(
+		logger, dispatcher, int64(pb.OpCodeUpdate_OPCODE_UPDATE_TABLE),
+		&pb.BlackjackUpdateDesk{
+			IsNewTurn: true,
+			InTurn:    userId,
+			Hand_N0:   s.GetCurrentHandN0(userId),
+			Actions: &pb.BlackjackLegalActions{
+				UserId:  userId,
+				Actions: s.GetLegalActionsByUserId(userId),
+			},
+			TimeBank: int64(s.GetTimeBank(userId)),
+		}, []runtime.Presence{presence}, nil, true,
+	)
+}
@@ -0,0 +1,44 @@
+package processor
+
+import (
+	"encoding/json"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/nk-nigeria/blackjack-module/entity"
+)
+
+// processSitOut lets a seated player sit out the next rounds or sit back in
+func (m *BaseProcessor) processSitOut(logger runtime.Logger,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	message runtime.MatchData,
+) {
+	userId := message.GetUserId()
+	req := &entity.SitOutRequest{}
+	if err := json.Unmarshal(message.GetData(), req); err != nil {
+		logger.WithField("user-id", userId).WithField("error", err).Error("error-parse-sit-out-request")
+		return
+	}
+	if !req.SitOut {
+		if s.SitIn(userId) {
+			logger.WithField("user_id", userId).Info("user sits back in")
+			m.notifySitOut(logger, dispatcher, s, nil)
+		}
+		return
+	}
+	if code := s.SitOut(userId); code != "" {
+		m.broadcastJson(logger, dispatcher, entity.OpCodeUpdateSitOutError,
+			&entity.SitOutError{Code: code}, []runtime.Presence{message}, true)
+		return
+	}
+	logger.WithField("user_id", userId).Info("user sits out")
+	m.notifySitOut(logger, dispatcher, s, nil)
+}
+
+func (m *BaseProcessor) notifySitOut(logger runtime.Logger,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	presences []runtime.Presence,
+) {
+	m.broadcastJson(logger, dispatcher, entity.OpCodeUpdateSitOut, s.GetSitOuts(), presences, true)
+}
@@ -0,0 +1,29 @@
+package processor
+
+import (
+	"context"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/nk-nigeria/blackjack-module/entity"
+)
+
+// sendTableSnapshot sends each presence the whole table as it sees it
+func (m *BaseProcessor) sendTableSnapshot(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	presences ...runtime.Presence,
+) {
+	for _, presence := range presences {
+		if presence == nil {
+			continue
+		}
+		snapshot := s.TableSnapshot(presence.GetUserId())
+		if wallet, err := entity.ReadWalletUser(ctx, nk, logger, presence.GetUserId()); err == nil {
+			snapshot.Wallet = wallet.Chips
+		}
+		m.broadcastJson(logger, dispatcher, entity.OpCodeUpdateTableSnapshot,
+			snapshot, []runtime.Presence{presence}, true)
+	}
+}
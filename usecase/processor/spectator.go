@@ -0,0 +1,128 @@
+package processor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/nk-nigeria/blackjack-module/entity"
+)
+
+// splitSpectators adds the presences accepted as spectators and returns the ones
+// joining for a seat
+func (m *BaseProcessor) splitSpectators(logger runtime.Logger,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	presences []runtime.Presence,
+) []runtime.Presence {
+	seated := make([]runtime.Presence, 0, len(presences))
+	spectators := make([]runtime.Presence, 0)
+	for _, presence := range presences {
+		if s.IsPendingSpectator(presence.GetUserId()) || s.IsSpectator(presence.GetUserId()) {
+			s.AddSpectator(presence)
+			spectators = append(spectators, presence)
+			continue
+		}
+		seated = append(seated, presence)
+	}
+	if len(spectators) > 0 {
+		logger.WithField("spectators", len(spectators)).Info("presences join as spectator")
+		m.notifySeatQueue(logger, dispatcher, s, spectators)
+	}
+	return seated
+}
+
+// removeSpectators drops leaving spectators and returns the seated presences that leave
+func (m *BaseProcessor) removeSpectators(logger runtime.Logger,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	presences []runtime.Presence,
+) []runtime.Presence {
+	seated := make([]runtime.Presence, 0, len(presences))
+	removed := false
+	for _, presence := range presences {
+		if s.RemoveSpectator(presence.GetUserId()) {
+			removed = true
+			continue
+		}
+		seated = append(seated, presence)
+	}
+	if removed {
+		m.notifySeatQueue(logger, dispatcher, s, nil)
+	}
+	return seated
+}
+
+// processSeatQueue lets a spectator join or leave the seat queue
+func (m *BaseProcessor) processSeatQueue(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	db *sql.DB,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	message runtime.MatchData,
+) {
+	req := &entity.SeatQueueRequest{}
+	if err := json.Unmarshal(message.GetData(), req); err != nil {
+		logger.WithField("user-id", message.GetUserId()).WithField("error", err).Error("error-parse-seat-queue-request")
+		return
+	}
+	changed := false
+	if req.Join {
+		changed = s.JoinSeatQueue(message.GetUserId())
+	} else {
+		changed = s.LeaveSeatQueue(message.GetUserId())
+	}
+	if !changed {
+		return
+	}
+	m.notifySeatQueue(logger, dispatcher, s, nil)
+	m.promoteSeatQueue(ctx, logger, nk, db, dispatcher, s)
+}
+
+// promoteSeatQueue seats the head of the seat queue while seats are free
+func (m *BaseProcessor) promoteSeatQueue(ctx context.Context,
+	logger runtime.Logger,
+	nk runtime.NakamaModule,
+	db *sql.DB,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+) {
+	promoted := make([]runtime.Presence, 0)
+	for {
+		presence := s.PopSeatQueue()
+		if presence == nil {
+			break
+		}
+		wallet, err := entity.ReadWalletUser(ctx, nk, logger, presence.GetUserId())
+		if err != nil || wallet.Chips < int64(s.Label.MarkUnit) {
+			// can't afford a seat, keeps watching
+			logger.WithField("user_id", presence.GetUserId()).Info("skip seat promotion, chip balance not enough")
+			s.AddSpectator(presence)
+			continue
+		}
+		s.AddPresence(ctx, nk, db, []runtime.Presence{presence})
+		promoted = append(promoted, presence)
+		logger.WithField("user_id", presence.GetUserId()).Info("spectator promoted to a seat")
+		m.broadcastJson(logger, dispatcher, entity.OpCodeUpdateSeatPromoted,
+			&entity.SeatPromoted{UserId: presence.GetUserId()}, nil, true)
+	}
+	if len(promoted) == 0 {
+		return
+	}
+	s.UpdateLabel()
+	m.notifySeatQueue(logger, dispatcher, s, nil)
+	m.notifyUserChange(ctx, nk, logger, db, dispatcher, s, nil)
+}
+
+func (m *BaseProcessor) notifySeatQueue(logger runtime.Logger,
+	dispatcher runtime.MatchDispatcher,
+	s *entity.MatchState,
+	presences []runtime.Presence,
+) {
+	m.broadcastJson(logger, dispatcher, entity.OpCodeUpdateSeatQueue, &entity.SeatQueueUpdate{
+		Queue:      s.GetSeatQueue(),
+		Spectators: len(s.GetSpectators()),
+	}, presences, true)
+}
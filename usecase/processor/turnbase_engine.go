@@ -180,3 +180,21 @@ func (m *TurnBaseEngine) GetRemainCountDown() int {
 func (m *TurnBaseEngine) IsGlob() bool {
 	return m.rounds[m.curRound].isGlob
 }
+
+// IsTimeout reports whether the current phase ran out, it moves on at the next Loop
+func (m *TurnBaseEngine) IsTimeout() bool {
+	return m.isInit && m.GetRemainCountDown() < 0
+}
+
+// ExtendCountDown gives the current phase d more from now
+func (m *TurnBaseEngine) ExtendCountDown(d time.Duration) {
+	m.countdownEndTime = time.Now().Add(d)
+}
+
+func (m *TurnBaseEngine) GetCurrentRound() string {
+	return m.rounds[m.curRound].code
+}
+
+func (m *TurnBaseEngine) GetCurrentPlayer() string {
+	return m.players[m.curPlayer]
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/rand"
 
+	"github.com/heroiclabs/nakama-common/runtime"
 	"github.com/nk-nigeria/blackjack-module/entity"
 	"github.com/nk-nigeria/blackjack-module/pkg/packager"
 	"github.com/nk-nigeria/cgp-common/bot"
@@ -14,6 +15,7 @@ import (
 // BlackjackBotIntegration implements BotIntegration for Blackjack game
 type BlackjackBotIntegration struct {
 	db           *sql.DB
+	logger       runtime.Logger
 	matchID      string
 	betAmount    int64
 	playerCount  int
@@ -26,16 +28,24 @@ type BlackjackBotIntegration struct {
 }
 
 // NewBlackjackBotIntegration creates a new Blackjack bot integration
-func NewBlackjackBotIntegration(db *sql.DB) *BlackjackBotIntegration {
+func NewBlackjackBotIntegration(db *sql.DB, logger runtime.Logger) *BlackjackBotIntegration {
+	integration := newBlackjackBotIntegration(db, logger, "")
+	integration.LoadBotConfig(context.Background())
+
+	return integration
+}
+
+// newBlackjackBotIntegration creates the integration of one match, config is set by the registry
+func newBlackjackBotIntegration(db *sql.DB, logger runtime.Logger, matchID string) *BlackjackBotIntegration {
 	integration := &BlackjackBotIntegration{
 		db:           db,
+		logger:       logger,
+		matchID:      matchID,
 		maxPlayers:   3, // Blackjack typically has 5 players max
 		minPlayers:   1, // Minimum 1 player to start
 		activeTables: 0, // Will be updated from game state
 	}
 	integration.botHelper = bot.NewBotIntegrationHelper(db, integration, entity.BotLoader)
-	integration.LoadBotConfig(context.Background())
-
 	return integration
 }
 
@@ -47,7 +57,7 @@ func (b *BlackjackBotIntegration) GetGameCode() string {
 
 // GetMinChipBalance returns minimum chip balance for bots
 func (b *BlackjackBotIntegration) GetMinChipBalance() int64 {
-	return 100000
+	return entity.BotLoaderMinChip
 }
 
 // GetMatchInfo returns current match information
@@ -75,6 +85,13 @@ func (b *BlackjackBotIntegration) AddBotToMatch(ctx context.Context, numBots int
 	}
 
 	state := procPkg.GetState()
+	// the join rules of the bot config only seat bots the fill policy has room for
+	if room := state.BotFillPolicy().BotRoom(state.BotFillInput()); numBots > room {
+		numBots = room
+	}
+	if numBots <= 0 {
+		return nil
+	}
 
 	// Add bots to match using existing processor method
 	err := procPkg.GetProcessor().AddBotToMatch(
@@ -110,6 +127,13 @@ func (b *BlackjackBotIntegration) RemoveBotFromMatch(ctx context.Context, botUse
 	}
 
 	state := procPkg.GetState()
+	// nor take the table below the seats the fill policy keeps occupied
+	if n := state.BotFillPolicy().BotsAboveMinOccupied(state.BotFillInput()); botLeftCount > n {
+		botLeftCount = n
+	}
+	if botLeftCount <= 0 {
+		return nil
+	}
 
 	botPresenceList := state.GetBotPresences()
 
@@ -119,15 +143,14 @@ func (b *BlackjackBotIntegration) RemoveBotFromMatch(ctx context.Context, botUse
 	}
 
 	if len(botUserIDs) == 0 {
-		fmt.Printf("[DEBUG] [BlackjackBotIntegration] No bots in presence list\n")
+		b.logger.WithField("match_id", b.matchID).Debug("no bot to remove")
 		return nil
 	}
 
 	// Ensure we don't try to remove more bots than available
 	if botLeftCount > len(botUserIDs) {
 		botLeftCount = len(botUserIDs)
-		fmt.Printf("[DEBUG] [BlackjackBotIntegration] Adjusted botLeftCount to %d (available bots: %d)\n",
-			botLeftCount, len(botUserIDs))
+		b.logger.WithField("match_id", b.matchID).WithField("bots", len(botUserIDs)).Debug("bot leave count capped to the seated bots")
 	}
 
 	// Random select bot userIDs to remove
@@ -151,12 +174,9 @@ func (b *BlackjackBotIntegration) RemoveBotFromMatch(ctx context.Context, botUse
 		availableBots = append(availableBots[:randomIndex], availableBots[randomIndex+1:]...)
 	}
 
-	fmt.Printf("[DEBUG] [BlackjackBotIntegration] Random selected %d bots to remove: %v\n",
-		len(selectedBotUserIDs), selectedBotUserIDs)
+	b.logger.WithField("match_id", b.matchID).WithField("bots", selectedBotUserIDs).Debug("bots selected to leave")
 
 	for _, selectedBotUserID := range selectedBotUserIDs {
-		fmt.Printf("[DEBUG] [BlackjackBotIntegration] Removing bot %s from match\n", selectedBotUserID)
-
 		err := procPkg.GetProcessor().RemoveBotFromMatch(
 			ctx,
 			procPkg.GetLogger(),
@@ -167,11 +187,10 @@ func (b *BlackjackBotIntegration) RemoveBotFromMatch(ctx context.Context, botUse
 			selectedBotUserID,
 		)
 		if err != nil {
-			fmt.Printf("[ERROR] [BlackjackBotIntegration] Failed to remove bot %s: %v\n", selectedBotUserID, err)
+			b.logger.WithField("match_id", b.matchID).WithField("user_id", selectedBotUserID).WithField("err", err).
+				Error("bot leave failed")
 			return err
 		}
-
-		fmt.Printf("[DEBUG] [BlackjackBotIntegration] Successfully removed bot %s\n", selectedBotUserID)
 	}
 
 	b.playerCount = state.GetPresenceSize()
@@ -223,11 +242,25 @@ func (b *BlackjackBotIntegration) SetMatchState(matchID string, betAmount int64,
 	b.activeTables = activeTables
 }
 
+// SyncMatchState updates the match info from the state of the match owning this integration
+func (b *BlackjackBotIntegration) SyncMatchState(state *entity.MatchState, activeTables int) {
+	b.SetMatchState(
+		state.GetMatchID(),
+		state.GetBetAmount(),
+		state.GetPresenceSize(),
+		len(state.Bots),
+		activeTables,
+	)
+	b.lastResult = state.GetLastResult()
+}
+
 // ProcessBotLogic processes all bot-related logic
 func (b *BlackjackBotIntegration) ProcessJoinBotLogic(ctx context.Context) error {
-	fmt.Printf("[DEBUG] [BlackjackBotIntegration] ProcessJoinBotLogic called for matchID=%s, betAmount=%d, playerCount=%d, botCount=%d\n",
-		b.matchID, b.betAmount, b.playerCount, b.botCount)
-
+	b.logger.WithField("match_id", b.matchID).
+		WithField("bet", b.betAmount).
+		WithField("players", b.playerCount).
+		WithField("bots", b.botCount).
+		Debug("process bot join logic")
 	return b.botHelper.ProcessJoinBotLogic(ctx)
 }
 
@@ -238,16 +271,13 @@ func (b *BlackjackBotIntegration) ProcessBotLeaveLogic(ctx context.Context) erro
 
 // CheckAndJoinExpiredBots checks if any bots should join based on their join time
 func (b *BlackjackBotIntegration) CheckAndJoinExpiredBots(ctx context.Context) (bool, error) {
-	fmt.Printf("[DEBUG] [BlackjackBotIntegration] CheckAndJoinExpiredBots called for matchID=%s, betAmount=%d, playerCount=%d\n",
-		b.matchID, b.betAmount, b.playerCount)
-
-	// Debug current bot config
-	config := b.botHelper.GetBotConfig()
-	fmt.Printf("[DEBUG] [BlackjackBotIntegration] Current bot config has %d join rules\n", len(config.BotJoinRules))
-
 	result, err := b.botHelper.CheckAndJoinExpiredBots(ctx)
-	fmt.Printf("[DEBUG] [BlackjackBotIntegration] CheckAndJoinExpiredBots result: joined=%v, err=%v\n", result, err)
-
+	b.logger.WithField("match_id", b.matchID).
+		WithField("bet", b.betAmount).
+		WithField("players", b.playerCount).
+		WithField("join_rules", len(b.botHelper.GetBotConfig().BotJoinRules)).
+		WithField("joined", result).
+		Debug("check expired bot joins")
 	return result, err
 }
 
@@ -258,26 +288,19 @@ func (b *BlackjackBotIntegration) GetBotHelper() *bot.BotIntegrationHelper {
 
 // LoadBotConfig loads bot configuration from database
 func (b *BlackjackBotIntegration) LoadBotConfig(ctx context.Context) error {
-	fmt.Printf("[DEBUG] [BlackjackBotIntegration] Loading bot config for game: %s\n", b.GetGameCode())
-
 	configLoader := bot.NewConfigLoader(b.db)
 	config, err := configLoader.LoadConfigFromDatabase(ctx, b.GetGameCode())
 	if err != nil {
-		fmt.Printf("[ERROR] [BlackjackBotIntegration] Failed to load bot config: %v\n", err)
+		b.logger.WithField("game", b.GetGameCode()).WithField("err", err).Error("load bot config failed")
 		return fmt.Errorf("failed to load bot config: %w", err)
 	}
 
-	fmt.Printf("[DEBUG] [BlackjackBotIntegration] Successfully loaded bot config:\n")
-	fmt.Printf("[DEBUG] [BlackjackBotIntegration] - BotJoinRules count: %d\n", len(config.BotJoinRules))
-	for i, rule := range config.BotJoinRules {
-		fmt.Printf("[DEBUG] [BlackjackBotIntegration] - Rule[%d]: minBet=%d, maxBet=%d, minUsers=%d, maxUsers=%d, joinPercent=%d\n",
-			i, rule.MinBet, rule.MaxBet, rule.MinUsers, rule.MaxUsers, rule.JoinPercent)
-	}
-	fmt.Printf("[DEBUG] [BlackjackBotIntegration] - BotLeaveRules count: %d\n", len(config.BotLeaveRules))
-	fmt.Printf("[DEBUG] [BlackjackBotIntegration] - BotCreateTableRules count: %d\n", len(config.BotCreateTableRules))
-
 	b.botHelper.SetBotConfig(config)
-	fmt.Printf("[DEBUG] [BlackjackBotIntegration] Bot config set to bot helper\n")
+	b.logger.WithField("game", b.GetGameCode()).
+		WithField("join_rules", len(config.BotJoinRules)).
+		WithField("leave_rules", len(config.BotLeaveRules)).
+		WithField("create_table_rules", len(config.BotCreateTableRules)).
+		Info("bot config loaded")
 	return nil
 }
 
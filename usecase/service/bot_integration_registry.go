@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/nk-nigeria/blackjack-module/entity"
+	"github.com/nk-nigeria/cgp-common/bot"
+)
+
+// BotIntegrationRegistry keeps one BlackjackBotIntegration per match so concurrent
+// tables don't overwrite each other's match info and pending bot requests.
+type BotIntegrationRegistry struct {
+	db           *sql.DB
+	logger       runtime.Logger
+	mu           sync.RWMutex
+	integrations map[string]*BlackjackBotIntegration
+	config       *bot.BotConfig
+}
+
+// NewBotIntegrationRegistry creates a registry and loads the shared bot config once
+func NewBotIntegrationRegistry(db *sql.DB, logger runtime.Logger) *BotIntegrationRegistry {
+	r := &BotIntegrationRegistry{
+		db:           db,
+		logger:       logger,
+		integrations: make(map[string]*BlackjackBotIntegration),
+	}
+	r.ReloadConfig(context.Background())
+	return r
+}
+
+// ReloadConfig reloads the bot config and applies it to every match
+func (r *BotIntegrationRegistry) ReloadConfig(ctx context.Context) error {
+	config, err := bot.NewConfigLoader(r.db).LoadConfigFromDatabase(ctx, entity.ModuleName)
+	if err != nil {
+		r.logger.WithField("err", err).Error("load bot config failed")
+		return fmt.Errorf("failed to load bot config: %w", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.config = config
+	for _, integration := range r.integrations {
+		integration.botHelper.SetBotConfig(config)
+	}
+	return nil
+}
+
+// Get returns the integration of matchID, creating it on first use
+func (r *BotIntegrationRegistry) Get(matchID string) *BlackjackBotIntegration {
+	r.mu.RLock()
+	integration, ok := r.integrations[matchID]
+	r.mu.RUnlock()
+	if ok {
+		return integration
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if integration, ok := r.integrations[matchID]; ok {
+		return integration
+	}
+	integration = newBlackjackBotIntegration(r.db, r.logger, matchID)
+	if r.config != nil {
+		integration.botHelper.SetBotConfig(r.config)
+	}
+	r.integrations[matchID] = integration
+	r.logger.WithField("match_id", matchID).WithField("active", len(r.integrations)).Debug("bot integration created")
+	return integration
+}
+
+// Remove drops the integration of a terminated match
+func (r *BotIntegrationRegistry) Remove(matchID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.integrations, matchID)
+	r.logger.WithField("match_id", matchID).WithField("active", len(r.integrations)).Debug("bot integration removed")
+}
+
+// Size returns the number of matches with a bot integration
+func (r *BotIntegrationRegistry) Size() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.integrations)
+}
@@ -0,0 +1,21 @@
+package smstates
+
+import (
+	"github.com/nk-nigeria/blackjack-module/pkg/global"
+	"github.com/nk-nigeria/blackjack-module/pkg/packager"
+	"github.com/nk-nigeria/blackjack-module/usecase/service"
+)
+
+// matchBotIntegration returns the bot integration of the match being processed,
+// synced with its current state
+func matchBotIntegration(procPkg *packager.ProcessorPackager) *service.BlackjackBotIntegration {
+	registry, ok := global.GetBotIntegrationRegistry().(*service.BotIntegrationRegistry)
+	if !ok {
+		registry = service.NewBotIntegrationRegistry(procPkg.GetDb(), procPkg.GetLogger())
+		global.SetBotIntegrationRegistry(registry)
+	}
+	state := procPkg.GetState()
+	integration := registry.Get(state.GetMatchID())
+	integration.SyncMatchState(state, registry.Size())
+	return integration
+}
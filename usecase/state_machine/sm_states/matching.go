@@ -2,7 +2,6 @@ package smstates
 
 import (
 	"context"
-	"strings"
 	"time"
 
 	// "github.com/nk-nigeria/blackjack-module/entity"
@@ -25,16 +24,6 @@ func (s *StateMatching) Enter(ctx context.Context, _ ...interface{}) error {
 	state := procPkg.GetState()
 	state.SetUpCountDown(time.Second * 5)
 	procPkg.GetLogger().Info("apply leave presence")
-	listPrecense := state.GetPresenceNotInteract(2)
-	if len(listPrecense) > 0 {
-		listUserId := make([]string, len(listPrecense))
-		for _, p := range listPrecense {
-			listUserId = append(listUserId, p.GetUserId())
-		}
-		procPkg.GetLogger().Info("Kick %d user from math %s",
-			len(listPrecense), strings.Join(listUserId, ","))
-		state.AddLeavePresence(listPrecense...)
-	}
 	procPkg.GetProcessor().ProcessApplyPresencesLeave(
 		procPkg.GetContext(),
 		procPkg.GetLogger(),
@@ -65,6 +54,10 @@ func (s *StateMatching) Process(ctx context.Context, args ...interface{}) error
 	if remain > 0 {
 		return nil
 	}
+	// no new round while an operator paused or is closing the table
+	if state.IsPaused() || state.IsClosing() {
+		return nil
+	}
 
 	if state.IsEnoughPlayer() {
 		s.Trigger(ctx, TriggerStateFinishSuccess)
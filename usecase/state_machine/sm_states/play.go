@@ -3,6 +3,7 @@ package smstates
 import (
 	"context"
 	"math"
+	"time"
 
 	"github.com/nk-nigeria/blackjack-module/entity"
 	"github.com/nk-nigeria/blackjack-module/pkg/packager"
@@ -68,6 +69,9 @@ func (s *StatePlay) Process(ctx context.Context, args ...interface{}) error {
 		return nil
 	}
 
+	// show the cards due since the last tick
+	state.Timeline().Run(time.Now())
+
 	procPkg.GetProcessor().ProcessTurnbase(ctx,
 		procPkg.GetLogger(),
 		procPkg.GetNK(),
@@ -79,43 +83,41 @@ func (s *StatePlay) Process(ctx context.Context, args ...interface{}) error {
 	// Get messages from real users
 	message := procPkg.GetMessages()
 
-	// Check if insurance round and trigger bot insurance decisions
+	// Check if insurance round and schedule bot insurance decisions
 	if state.IsAllowInsurance() {
-		procPkg.GetLogger().Info("[play] Insurance round - checking bots")
-		// Insurance round - all bots decide simultaneously
+		// Insurance round - each bot thinks on its own
 		for _, presence := range state.GetBotPresences() {
 			if botPresence, ok := presence.(*bot.BotPresence); ok {
 				userId := botPresence.GetUserId()
 				// Check if bot hasn't made insurance decision yet
-				if !state.HasInsuranceBet(userId) {
-					procPkg.GetLogger().Info("[play] Bot insurance check for: %s", userId)
-					// Bot decides whether to take insurance
-					state.BotInsuranceAction(botPresence)
+				if !state.HasInsuranceBet(userId) && state.ScheduleBotInsurance(procPkg.GetLogger(), botPresence) {
+					procPkg.GetLogger().Info("[play] Bot insurance scheduled for: %s", userId)
 				}
 			}
 		}
 	}
 
-	// Check if current turn is a bot and trigger bot action
-	currentTurn := state.GetCurrentTurn()
-	if currentTurn != "" && state.IsAllowAction() {
-		// Check if current turn is a bot
+	// Schedule the decision of the bots in turn with a think time, all of them when
+	// players decide at once
+	if state.IsAllowAction() {
 		for _, presence := range state.GetBotPresences() {
-			if botPresence, ok := presence.(*bot.BotPresence); ok && botPresence.GetUserId() == currentTurn {
-				// Current turn is a bot - trigger bot action
-				procPkg.GetLogger().Info("[play] Bot turn detected for: %s", currentTurn)
-
-				// Get legal actions for this bot
-				legalActions := state.GetLegalActions()
-				if len(legalActions) > 0 {
-					// Bot decides action and queues message
-					state.BotAction(botPresence, legalActions)
+			if botPresence, ok := presence.(*bot.BotPresence); ok && state.IsInTurn(botPresence.GetUserId()) {
+				if state.ScheduleBotAction(procPkg.GetLogger(), botPresence) {
+					procPkg.GetLogger().Info("[play] Bot action scheduled for: %s", botPresence.GetUserId())
 				}
-				break
+			}
+		}
+		// Hands of a disconnected player are played for them while the seat is held
+		for _, presence := range state.GetPlayingPresences() {
+			if state.ScheduleAutoPlay(procPkg.GetLogger(), presence.GetUserId()) {
+				procPkg.GetLogger().Info("[play] Auto play scheduled for disconnected: %s", presence.GetUserId())
 			}
 		}
 	}
 
+	// Fire bot decisions whose think time elapsed, they queue their messages
+	state.RunBotDecisions()
+
 	// Get bot messages from queue and merge with user messages
 	botMessages := state.Messages()
 	message = append(message, botMessages...)
@@ -131,6 +133,8 @@ func (s *StatePlay) Process(ctx context.Context, args ...interface{}) error {
 			state,
 		)
 	}
+	// decisions whose action was refused are decided again on a later tick
+	state.SettleBotDecisions()
 
 	if state.IsNeedNotifyCountDown() {
 		remainCountDown := int(math.Round(state.GetRemainCountDown()))
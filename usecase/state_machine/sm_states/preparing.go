@@ -3,12 +3,9 @@ package smstates
 import (
 	"context"
 	"math"
-	"strings"
 
 	"github.com/nk-nigeria/blackjack-module/entity"
-	"github.com/nk-nigeria/blackjack-module/pkg/global"
 	"github.com/nk-nigeria/blackjack-module/pkg/packager"
-	"github.com/nk-nigeria/blackjack-module/usecase/service"
 	"github.com/nk-nigeria/cgp-common/bot"
 	pb "github.com/nk-nigeria/cgp-common/proto"
 )
@@ -29,17 +26,14 @@ func (s *StatePreparing) Enter(ctx context.Context, _ ...interface{}) error {
 	state.InitUserBet()
 	procPkg.GetLogger().Info("state %v", state.Presences)
 	state.SetUpCountDown(entity.GameStateDuration[state.GetGameState()])
-	// remove all user not interact 2 game conti
-	listPrecense := state.GetPresenceNotInteract(2)
-	if len(listPrecense) > 0 {
-		listUserId := make([]string, len(listPrecense))
-		for _, p := range listPrecense {
-			listUserId = append(listUserId, p.GetUserId())
-		}
-		procPkg.GetLogger().Info("Kick %d user from math %s",
-			len(listPrecense), strings.Join(listUserId, ","))
-		state.AddLeavePresence(listPrecense...)
-	}
+	// warn, sit out or remove idle users following the AFK policy of the table
+	procPkg.GetProcessor().ProcessAfk(ctx,
+		procPkg.GetLogger(),
+		procPkg.GetNK(),
+		procPkg.GetDb(),
+		procPkg.GetDispatcher(),
+		state,
+	)
 	procPkg.GetProcessor().ProcessApplyPresencesLeave(ctx,
 		procPkg.GetLogger(),
 		procPkg.GetNK(),
@@ -48,29 +42,18 @@ func (s *StatePreparing) Enter(ctx context.Context, _ ...interface{}) error {
 		state,
 	)
 
-	// Initialize bot integration if not already done
-	botIntegration := global.GetGlobalBotIntegration()
-	if botIntegration == nil {
-		botIntegration = service.NewBlackjackBotIntegration(procPkg.GetDb())
-		global.SetGlobalBotIntegration(botIntegration)
-	}
-
-	// Type assert to get the actual bot integration
-	if blackjackBotIntegration, ok := botIntegration.(*service.BlackjackBotIntegration); ok {
-		// Set match state for bot decision making before processing bot logic
-		blackjackBotIntegration.SetMatchState(
-			state.GetMatchID(),
-			state.GetBetAmount(),
-			state.GetPresenceSize(),
-			0, // lastResult - chưa có kết quả game
-			1, // activeTables
-		)
-
-		// Process bot join logic during preparing phase
-		if err := blackjackBotIntegration.ProcessJoinBotLogic(ctx); err != nil {
-			procPkg.GetLogger().Error("Failed to process bot join logic: %v", err)
-		}
+	// Process bot join logic during preparing phase, seats are bounded by the fill policy
+	if err := matchBotIntegration(procPkg).ProcessJoinBotLogic(ctx); err != nil {
+		procPkg.GetLogger().Error("Failed to process bot join logic: %v", err)
 	}
+	procPkg.GetProcessor().ProcessBotFill(ctx,
+		procPkg.GetLogger(),
+		procPkg.GetNK(),
+		procPkg.GetDb(),
+		procPkg.GetDispatcher(),
+		state,
+		"preparing",
+	)
 
 	procPkg.GetProcessor().NotifyUpdateGameState(
 		state,
@@ -106,6 +89,7 @@ func (s *StatePreparing) Process(ctx context.Context, args ...interface{}) error
 	state := procPkg.GetState()
 	remain := state.GetRemainCountDown()
 	if state.GetPresenceNotBotSize() == 0 {
+		procPkg.GetProcessor().ProcessPropBetRefund(ctx, procPkg.GetLogger(), procPkg.GetNK(), procPkg.GetDispatcher(), state)
 		s.Trigger(ctx, TriggerStateFinishFailed)
 		return nil
 	}
@@ -129,27 +113,25 @@ func (s *StatePreparing) Process(ctx context.Context, args ...interface{}) error
 	}
 
 	// Check if bot should join based on time (similar to Baccarat module)
-	if state.GetPresenceSize() < entity.MaxPresences {
+	if state.GetPresenceSize() < state.MaxPresences {
 		// Check if bot should join based on time
 		botCtx := packager.GetContextWithProcessorPackager(procPkg)
-		if blackjackBotIntegration, ok := global.GetGlobalBotIntegration().(*service.BlackjackBotIntegration); ok {
-			joined, err := blackjackBotIntegration.CheckAndJoinExpiredBots(botCtx)
-			if err != nil {
-				procPkg.GetLogger().Error("[preparing] Bot join error: %v", err)
-			} else if joined {
-				procPkg.GetLogger().Info("[preparing] Bot joined based on time")
-				// Initialize betting turn for newly joined bots
-				for _, presence := range state.GetBotPresences() {
-					if botPresence, ok := presence.(*bot.BotPresence); ok {
-						if !state.IsBet(botPresence.GetUserId()) {
-							state.InitTurnBot(botPresence)
-						}
+		joined, err := matchBotIntegration(procPkg).CheckAndJoinExpiredBots(botCtx)
+		if err != nil {
+			procPkg.GetLogger().Error("[preparing] Bot join error: %v", err)
+		} else if joined {
+			procPkg.GetLogger().Info("[preparing] Bot joined based on time")
+			// Initialize betting turn for newly joined bots
+			for _, presence := range state.GetBotPresences() {
+				if botPresence, ok := presence.(*bot.BotPresence); ok {
+					if !state.IsBet(botPresence.GetUserId()) {
+						state.InitTurnBot(botPresence)
 					}
 				}
 			}
 		}
 	} else {
-		procPkg.GetLogger().Info("[preparing] Skip bot join - maximum players reached (%d)", entity.MaxPresences)
+		procPkg.GetLogger().Info("[preparing] Skip bot join - maximum players reached (%d)", state.MaxPresences)
 	}
 
 	if remain <= 0 {
@@ -157,7 +139,8 @@ func (s *StatePreparing) Process(ctx context.Context, args ...interface{}) error
 		if state.IsReadyToPlay() {
 			s.Trigger(ctx, TriggerStateFinishSuccess)
 		} else {
-			// change to wait
+			// change to wait, nothing to settle prop bets against
+			procPkg.GetProcessor().ProcessPropBetRefund(ctx, procPkg.GetLogger(), procPkg.GetNK(), procPkg.GetDispatcher(), state)
 			s.Trigger(ctx, TriggerStateFinishFailed)
 		}
 		return nil
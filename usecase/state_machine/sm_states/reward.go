@@ -3,11 +3,10 @@ package smstates
 import (
 	"context"
 	"math"
+	"time"
 
 	"github.com/nk-nigeria/blackjack-module/entity"
-	"github.com/nk-nigeria/blackjack-module/pkg/global"
 	"github.com/nk-nigeria/blackjack-module/pkg/packager"
-	"github.com/nk-nigeria/blackjack-module/usecase/service"
 	pb "github.com/nk-nigeria/cgp-common/proto"
 )
 
@@ -27,30 +26,22 @@ func (s *StateReward) Enter(ctx context.Context, _ ...interface{}) error {
 	state := procPkg.GetState()
 	state.SetUpCountDown(entity.GameStateDuration[state.GetGameState()])
 
-	// Initialize bot integration if not already done
-	botIntegration := global.GetGlobalBotIntegration()
-	if botIntegration == nil {
-		botIntegration = service.NewBlackjackBotIntegration(procPkg.GetDb())
-		global.SetGlobalBotIntegration(botIntegration)
+	// Process bot leave logic during reward phase, the fill policy keeps its occupied seats
+	if err := matchBotIntegration(procPkg).ProcessBotLeaveLogic(ctx); err != nil {
+		procPkg.GetLogger().Error("Failed to process bot leave logic: %v", err)
 	}
 
-	// Type assert to get the actual bot integration
-	if blackjackBotIntegration, ok := botIntegration.(*service.BlackjackBotIntegration); ok {
-		// Set match state for bot decision making
-		blackjackBotIntegration.SetMatchState(
-			state.GetMatchID(),
-			state.GetBetAmount(),
-			state.GetPresenceSize(),
-			state.GetLastResult(), // Use actual game result
-			1,                     // activeTables
-		)
-
-		// Process bot leave logic during reward phase
-		if err := blackjackBotIntegration.ProcessBotLeaveLogic(ctx); err != nil {
-			procPkg.GetLogger().Error("Failed to process bot leave logic: %v", err)
-		}
+	// process finish, the countdown is stretched over the dealer play,
+	// a round refunded by an operator is not settled
+	if !state.IsRoundRefunded() {
+		procPkg.GetProcessor().ProcessFinishGame(
+			procPkg.GetContext(),
+			procPkg.GetLogger(),
+			procPkg.GetNK(),
+			procPkg.GetDb(),
+			procPkg.GetDispatcher(),
+			state)
 	}
-
 	procPkg.GetProcessor().NotifyUpdateGameState(
 		state,
 		procPkg.GetLogger(),
@@ -60,14 +51,6 @@ func (s *StateReward) Enter(ctx context.Context, _ ...interface{}) error {
 			CountDown: int64(math.Round(state.GetRemainCountDown())),
 		},
 	)
-	// process finish
-	procPkg.GetProcessor().ProcessFinishGame(
-		procPkg.GetContext(),
-		procPkg.GetLogger(),
-		procPkg.GetNK(),
-		procPkg.GetDb(),
-		procPkg.GetDispatcher(),
-		state)
 
 	return nil
 }
@@ -76,6 +59,9 @@ func (s *StateReward) Exit(ctx context.Context, _ ...interface{}) error {
 	procPkg := packager.GetProcessorPackagerFromContext(ctx)
 	state := procPkg.GetState()
 	state.ResetBalanceResult()
+	state.ResetDealerSequence()
+	procPkg.GetProcessor().ProcessBotRetire(procPkg.GetContext(), procPkg.GetLogger(), procPkg.GetNK(), procPkg.GetDb(), procPkg.GetDispatcher(), state)
+	procPkg.GetProcessor().ProcessBotFill(procPkg.GetContext(), procPkg.GetLogger(), procPkg.GetNK(), procPkg.GetDb(), procPkg.GetDispatcher(), state, "reward")
 	procPkg.GetProcessor().ProcessMatchKick(procPkg.GetContext(), procPkg.GetLogger(), procPkg.GetNK(), procPkg.GetDb(), procPkg.GetDispatcher(), state)
 	return nil
 }
@@ -83,6 +69,8 @@ func (s *StateReward) Exit(ctx context.Context, _ ...interface{}) error {
 func (s *StateReward) Process(ctx context.Context, args ...interface{}) error {
 	procPkg := packager.GetProcessorPackagerFromContext(ctx)
 	state := procPkg.GetState()
+	// show the dealer cards and results due since the last tick
+	state.Timeline().Run(time.Now())
 	message := procPkg.GetMessages()
 	if len(message) > 0 {
 		procPkg.GetProcessor().ProcessMessageFromUser(ctx,
@@ -92,7 +80,7 @@ func (s *StateReward) Process(ctx context.Context, args ...interface{}) error {
 			procPkg.GetDispatcher(),
 			message, procPkg.GetState())
 	}
-	if remain := state.GetRemainCountDown(); remain <= 0 {
+	if remain := state.GetRemainCountDown(); remain <= 0 && state.Timeline().Done() {
 		s.Trigger(ctx, TriggerStateFinishSuccess)
 	}
 	return nil